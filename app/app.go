@@ -56,6 +56,7 @@ func (app *App) Program() {
 	}
 
 	semantic := semanticAnalyzer.NewSemanticAnalyzer(tree)
+	semantic.SetSourceDir(filepath.Dir(app.sourceFilePath))
 	scope, err := semantic.Start()
 	if err != nil {
 		panic(err)
@@ -0,0 +1,207 @@
+package assets
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//Load reads a file to embed it in the rom. Images (PBM, PGM and PNG) are sliced into sprites of 8 pixels wide,
+//any other file is embedded as it is
+func Load(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pbm", ".pgm":
+		pixels, err := decodeNetpbm(content)
+		if err != nil {
+			return nil, err
+		}
+		return sprites(pixels), nil
+	case ".png":
+		img, err := png.Decode(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		return sprites(imagePixels(img)), nil
+	default:
+		return content, nil
+	}
+}
+
+//sprites slices an image into columns of 8 pixels wide, the last one filled with pixels off if needed,
+//and returns a byte for each row of each column, from left to right and from top to bottom.
+//The leftmost pixel of a row is the most significant bit of its byte
+func sprites(pixels [][]bool) []byte {
+	values := make([]byte, 0)
+	if len(pixels) == 0 {
+		return values
+	}
+	width := len(pixels[0])
+	for column := 0; column < width; column += 8 {
+		for _, row := range pixels {
+			value := byte(0)
+			for i := 0; i < 8 && column+i < width; i++ {
+				if row[column+i] {
+					value |= 0x80 >> i
+				}
+			}
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+//imagePixels turns on the dark pixels of an image, ignoring the transparent ones
+func imagePixels(img image.Image) [][]bool {
+	bounds := img.Bounds()
+	pixels := make([][]bool, 0)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := make([]bool, 0)
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			luminance := (299*r + 587*g + 114*b) / 1000
+			row = append(row, a >= 0x8000 && luminance < a/2)
+		}
+		pixels = append(pixels, row)
+	}
+	return pixels
+}
+
+//decodeNetpbm decodes a PBM (P1 or P4) or PGM (P2 or P5) image. In PBM images the pixels on are the ones set to 1,
+//in PGM images the dark ones
+func decodeNetpbm(content []byte) ([][]bool, error) {
+	reader := bufio.NewReader(bytes.NewReader(content))
+	magic, err := netpbmField(reader)
+	if err != nil {
+		return nil, err
+	}
+	isBitmap := magic == "P1" || magic == "P4"
+	isGraymap := magic == "P2" || magic == "P5"
+	if !isBitmap && !isGraymap {
+		return nil, errors.New("unknown netpbm format " + magic)
+	}
+
+	header := []int{0, 0, 1} //width, height and the max value of the gray scale, which bitmaps don't have
+	if isBitmap {
+		header = header[:2]
+	}
+	for i := range header {
+		field, err := netpbmField(reader)
+		if err != nil {
+			return nil, err
+		}
+		header[i], err = strconv.Atoi(field)
+		if err != nil || header[i] <= 0 {
+			return nil, errors.New("invalid netpbm header")
+		}
+	}
+	width, height := header[0], header[1]
+	if isGraymap && header[2] > 0xFF {
+		//the gray of each pixel of a binary graymap whose max value is greater than 255 takes 2 bytes
+		return nil, errors.New("graymaps with a max gray value greater than 255 aren't supported")
+	}
+
+	pixels := make([][]bool, height)
+	for y := range pixels {
+		pixels[y] = make([]bool, width)
+		switch magic {
+		case "P4":
+			//each row is packed in bytes, with the leftmost pixel in the most significant bit
+			row := make([]byte, (width+7)/8)
+			_, err = io.ReadFull(reader, row)
+			if err != nil {
+				return nil, err
+			}
+			for x := range pixels[y] {
+				pixels[y][x] = row[x/8]&(0x80>>(x%8)) != 0
+			}
+		case "P5":
+			maxGray := header[2]
+			for x := range pixels[y] {
+				gray, err := reader.ReadByte()
+				if err != nil {
+					return nil, err
+				}
+				pixels[y][x] = int(gray) < (maxGray+1)/2
+			}
+		default:
+			for x := range pixels[y] {
+				pixels[y][x], err = netpbmASCIIPixel(reader, isBitmap, header)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return pixels, nil
+}
+
+//netpbmASCIIPixel reads a pixel of a P1 or P2 image
+func netpbmASCIIPixel(reader *bufio.Reader, isBitmap bool, header []int) (bool, error) {
+	var field string
+	var err error
+	if isBitmap {
+		//the pixels of a P1 image don't need to be separated by whitespaces
+		field, err = netpbmToken(reader, 1)
+	} else {
+		field, err = netpbmField(reader)
+	}
+	if err != nil {
+		return false, err
+	}
+	value, err := strconv.Atoi(field)
+	if err != nil {
+		return false, errors.New("invalid netpbm pixel " + field)
+	}
+	if isBitmap {
+		return value == 1, nil
+	}
+	return value < (header[2]+1)/2, nil
+}
+
+//netpbmField reads the next field of a netpbm image, skipping whitespaces and comments
+func netpbmField(reader *bufio.Reader) (string, error) {
+	return netpbmToken(reader, 0)
+}
+
+//netpbmToken reads up to "length" chars (all of them if length is 0) of the next field of a netpbm image,
+//skipping whitespaces and comments. After the last field of the header, it consumes a single whitespace,
+//as the binary formats expect
+func netpbmToken(reader *bufio.Reader, length int) (string, error) {
+	field := make([]byte, 0)
+	for {
+		char, err := reader.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(field) > 0 {
+				return string(field), nil
+			}
+			return "", err
+		}
+		switch {
+		case char == '#' && len(field) == 0:
+			_, err = reader.ReadString('\n')
+			if err != nil {
+				return "", err
+			}
+		case char == ' ' || char == '\t' || char == '\r' || char == '\n':
+			if len(field) > 0 {
+				return string(field), nil
+			}
+		default:
+			field = append(field, char)
+			if len(field) == length {
+				return string(field), nil
+			}
+		}
+	}
+}
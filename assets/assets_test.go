@@ -0,0 +1,117 @@
+package assets
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	type cases struct {
+		description    string
+		file           string
+		content        []byte
+		expectedValues []byte
+		isValid        bool
+	}
+
+	//a 10x2 png whose dark pixels are the first and the last ones of the first row
+	img := image.NewGray(image.Rect(0, 0, 10, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	img.SetGray(0, 0, color.Gray{})
+	img.SetGray(9, 0, color.Gray{})
+	encoded := new(bytes.Buffer)
+	err := png.Encode(encoded, img)
+	assert.NoError(t, err)
+
+	testCases := []cases{
+		{
+			description:    "binary file",
+			file:           "data.bin",
+			content:        []byte{1, 2, 3, 0xFF},
+			expectedValues: []byte{1, 2, 3, 0xFF},
+			isValid:        true,
+		},
+		{
+			description:    "P1 bitmap",
+			file:           "ship.pbm",
+			content:        []byte("P1\n# a comment\n4 2\n1001\n0110\n"),
+			expectedValues: []byte{0x90, 0x60},
+			isValid:        true,
+		},
+		{
+			description:    "P4 bitmap wider than a sprite",
+			file:           "wide.pbm",
+			content:        append([]byte("P4 10 1\n"), 0xFF, 0xC0),
+			expectedValues: []byte{0xFF, 0xC0},
+			isValid:        true,
+		},
+		{
+			description:    "P2 graymap",
+			file:           "gray.pgm",
+			content:        []byte("P2\n3 1\n15\n0 15 7\n"),
+			expectedValues: []byte{0xA0},
+			isValid:        true,
+		},
+		{
+			description:    "P5 graymap",
+			file:           "gray.pgm",
+			content:        append([]byte("P5 3 1 255\n"), 0xFF, 0x00, 0x80),
+			expectedValues: []byte{0x40},
+			isValid:        true,
+		},
+		{
+			description: "P5 graymap with a max gray value greater than 255",
+			file:        "deep.pgm",
+			content:     append([]byte("P5 1 1 65535\n"), 0x00, 0x00),
+			isValid:     false,
+		},
+		{
+			description: "P2 graymap with a max gray value greater than 255",
+			file:        "deep.pgm",
+			content:     []byte("P2 1 1 1000\n0\n"),
+			isValid:     false,
+		},
+		{
+			description: "unknown netpbm format",
+			file:        "color.pbm",
+			content:     []byte("P3 1 1 255\n0 0 0\n"),
+			isValid:     false,
+		},
+		{
+			description: "truncated P4 bitmap",
+			file:        "short.pbm",
+			content:     []byte("P4 8 2\n"),
+			isValid:     false,
+		},
+		{
+			description:    "png",
+			file:           "alien.png",
+			content:        encoded.Bytes(),
+			expectedValues: []byte{0x80, 0x00, 0x40, 0x00},
+			isValid:        true,
+		},
+	}
+
+	dir := t.TempDir()
+	for _, scenario := range testCases {
+		path := filepath.Join(dir, scenario.file)
+		err := os.WriteFile(path, scenario.content, 0644)
+		assert.NoError(t, err)
+
+		values, err := Load(path)
+		if !scenario.isValid {
+			assert.Error(t, err, scenario.description)
+			continue
+		}
+		assert.NoError(t, err, scenario.description)
+		assert.Equal(t, scenario.expectedValues, values, scenario.description)
+	}
+}
@@ -34,3 +34,20 @@ func (node *Node) AddChild(child *Node) {
 	child.Parent = node
 	node.Children = append(node.Children, child)
 }
+
+//NewCompoundOperation returns the operation that a compound assignment (like x += 2), an increment or a decrement
+//applies to its variable (like x + 2). The left child of the operation is the variable of the assignment itself
+func NewCompoundOperation(assignment *Node) *Node {
+	const TARGET = 0
+	const VALUE = 1
+	line := assignment.Value.Line
+	operator, _ := token.LookupCompoundAssignment(assignment.Value.Type)
+	operation := NewNode(token.NewToken(operator, string(operator), line))
+	value := NewNode(token.NewToken(token.BYTE, "1", line)) //increments and decrements add or subtract 1
+	if len(assignment.Children) > VALUE {
+		value = assignment.Children[VALUE]
+	}
+	//the children keep their parents, because the operation isn't part of the tree
+	operation.Children = []*Node{assignment.Children[TARGET], value}
+	return operation
+}
@@ -0,0 +1,413 @@
+package constant
+
+import (
+	"github.com/NoetherianRing/c8-compiler/ast"
+	"github.com/NoetherianRing/c8-compiler/symboltable"
+	"github.com/NoetherianRing/c8-compiler/token"
+	"strconv"
+)
+
+const (
+	True  = 1
+	False = 0
+)
+
+//Reason tells why the value of an expression can or can't be computed at compile time
+type Reason int
+
+const (
+	Known          Reason = iota //the value was computed
+	Overflow                     //the value was computed, but an operation wrapped around because its result didn't fit
+	DivisionByZero               //the expression divides by zero
+	Unknown                      //the expression reads a variable or calls a function, so its value is known when the program runs
+)
+
+//Evaluate computes the value of an expression at compile time. It returns false if the value of the expression
+//can't be known until the program runs, or if it divides by zero.
+//The operations wrap around as they do in a register, and booleans are evaluated as True or False
+func Evaluate(node *ast.Node, scope *symboltable.Scope) (int, bool) {
+	value, _, reason := Compute(node, scope, 1)
+	return value, reason <= Overflow
+}
+
+//EvaluateWithSize computes the value of an expression at compile time as Evaluate does, and also returns its size in bytes,
+//which is 2 if the value is a word
+func EvaluateWithSize(node *ast.Node, scope *symboltable.Scope) (int, int, bool) {
+	value, size, reason := Compute(node, scope, 1)
+	return value, size, reason <= Overflow
+}
+
+//Compute computes the value of an expression at compile time and its size in bytes, operating its values with at least
+//the given size, so the byte operations of an expression saved in a word are made as word operations.
+//Returns Known if the value was computed, or the reason why it wasn't, or why it may not be the expected one
+func Compute(node *ast.Node, scope *symboltable.Scope, size int) (int, int, Reason) {
+	value, size, reason := evaluate(node, scope, size)
+	if reason > Overflow {
+		return 0, 0, reason
+	}
+	return value & mask(size), size, reason
+}
+
+//Exact computes the value of an expression at compile time as Compute does with bytes, but the value isn't wrapped
+//around, so it is negative if it is below zero
+func Exact(node *ast.Node, scope *symboltable.Scope) (int, Reason) {
+	value, _, reason := evaluate(node, scope, 1)
+	if reason > Overflow {
+		return 0, reason
+	}
+	return value, reason
+}
+
+//evaluate computes the value of an expression and its size in bytes, which is at least floor. The values of additions,
+//subtractions, multiplications, left shifts and negations are exact while they fit in their size, so they can be negative,
+//and the rest of them are the bits of the value
+func evaluate(node *ast.Node, scope *symboltable.Scope, floor int) (int, int, Reason) {
+	switch node.Value.Type {
+	case token.BYTE:
+		value, err := strconv.Atoi(node.Value.Literal)
+		if err != nil {
+			return 0, 0, Unknown
+		}
+		if value > 0xFF {
+			return value, 2, Known
+		}
+		return value, floor, Known
+	case token.BOOL:
+		if node.Value.Literal == token.TRUE {
+			return True, 1, Known
+		}
+		return False, 1, Known
+	case token.IDENT:
+		symbol, ok := scope.Symbols[node.Value.Literal]
+		if !ok || !symbol.IsConstant {
+			return 0, 0, Unknown
+		}
+		if symboltable.IsInt8(symbol.DataType) {
+			return signExtend(symbol.Value), floor, Known
+		}
+		return symbol.Value, larger(symboltable.GetSize(symbol.DataType), floor), Known
+	case token.RPAREN:
+		//a parenthesis whose child is an identifier is a call, unless the identifier names a type
+		if node.Children[0].Value.Type == token.IDENT && !isAType(node.Children[0], scope) {
+			return 0, 0, Unknown
+		}
+		if len(node.Children) == 2 {
+			return convert(node, scope, floor)
+		}
+		return evaluate(node.Children[0], scope, floor)
+	case token.BANG:
+		value, _, reason := evaluate(node.Children[0], scope, 1)
+		return value ^ True, 1, reason
+	case token.QUESTION:
+		//a conditional expression can be evaluated if its condition and the chosen branch can
+		condition, _, reason := evaluate(node.Children[0], scope, 1)
+		if reason > Overflow {
+			return 0, 0, reason
+		}
+		branches := node.Children[1]
+		value, size, branchReason := evaluate(branches.Children[1], scope, floor)
+		if condition == True {
+			value, size, branchReason = evaluate(branches.Children[0], scope, floor)
+		}
+		return value, size, worse(reason, branchReason)
+	case token.TILDE:
+		value, size, reason := evaluate(node.Children[0], scope, floor)
+		return ^value & mask(size), size, reason
+	case token.MINUS:
+		//a minus with a single child is a negation, like the one of a negative literal
+		if len(node.Children) == 1 {
+			value, size, reason := evaluate(node.Children[0], scope, floor)
+			if reason > Overflow {
+				return 0, 0, reason
+			}
+			value, fitReason := fit(-value, size)
+			return value, size, worse(reason, fitReason)
+		}
+	}
+
+	//the rest of the expressions that can be evaluated are binary operations
+	if len(node.Children) != 2 {
+		return 0, 0, Unknown
+	}
+	left, leftSize, leftReason := evaluate(node.Children[0], scope, floor)
+	if leftReason > Overflow {
+		return 0, 0, leftReason
+	}
+	right, rightSize, rightReason := evaluate(node.Children[1], scope, floor)
+	if rightReason > Overflow {
+		return 0, 0, rightReason
+	}
+	reason := worse(leftReason, rightReason)
+	size := larger(leftSize, rightSize)
+	var value int
+	var operationReason Reason
+	switch node.Value.Type {
+	case token.PLUS, token.MINUS, token.ASTERISK:
+		value, operationReason = operate(node.Value.Type, left, right)
+		if operationReason == Known {
+			value, operationReason = fit(value, size)
+		}
+	case token.LTLT:
+		//a shift keeps the size of the value shifted, the bits shifted out of a word overflow anyway
+		size = leftSize
+		right &= mask(rightSize)
+		if right > 16 {
+			right = 17
+		}
+		value, operationReason = fit(left<<right, size)
+	case token.GTGT:
+		//a signed value keeps its sign when it's shifted to the right
+		size = leftSize
+		left &= mask(leftSize)
+		if signed(node.Children[0], scope) {
+			left = signExtend(left)
+		}
+		value = (left >> (right & mask(rightSize))) & mask(leftSize)
+	case token.EQEQ, token.NOTEQ, token.LT, token.LTEQ, token.GT, token.GTEQ, token.LAND, token.LOR:
+		left &= mask(size)
+		right &= mask(size)
+		if signed(node.Children[0], scope) || signed(node.Children[1], scope) {
+			left = signExtend(left)
+			right = signExtend(right)
+		}
+		size = 1
+		value, operationReason = operate(node.Value.Type, left, right)
+	default:
+		value, operationReason = operate(node.Value.Type, left&mask(size), right&mask(size))
+	}
+	if operationReason > Overflow {
+		return 0, 0, operationReason
+	}
+	return value, size, worse(reason, operationReason)
+}
+
+//larger returns the larger of two sizes
+func larger(size1 int, size2 int) int {
+	if size1 > size2 {
+		return size1
+	}
+	return size2
+}
+
+//worse returns the reason that prevents the most from knowing the value of an expression
+func worse(reason1 Reason, reason2 Reason) Reason {
+	if reason1 > reason2 {
+		return reason1
+	}
+	return reason2
+}
+
+//fit checks that the exact value of an operation fits in the given size: a byte holds a value from -128, the least int8,
+//to 255, the greatest byte, and a word a value from -32768 to 65535. If it doesn't fit, it returns the value wrapped
+//around as it is in a register, and Overflow
+func fit(value int, size int) (int, Reason) {
+	if value > mask(size) || value < -(mask(size)+1)/2 {
+		return value & mask(size), Overflow
+	}
+	return value, Known
+}
+
+//isAType checks if an identifier is the name of a type
+func isAType(ident *ast.Node, scope *symboltable.Scope) bool {
+	symbol, ok := scope.Symbols[ident.Value.Literal]
+	return ok && symbol.IsType
+}
+
+//convert computes the value of a conversion, whose first child is the data type the value of the second one is converted to
+func convert(node *ast.Node, scope *symboltable.Scope, floor int) (int, int, Reason) {
+	const DATATYPE = 0
+	const VALUE = 1
+	value, size, reason := evaluate(node.Children[VALUE], scope, 1)
+	if reason > Overflow {
+		return 0, 0, reason
+	}
+	switch node.Children[DATATYPE].Value.Type {
+	case token.TYPEWORD:
+		//an int8 keeps its sign when it's converted to a word
+		if size == 1 && signed(node.Children[VALUE], scope) {
+			value = signExtend(value & 0xFF)
+		}
+		return value & 0xFFFF, 2, reason
+	case token.ASTERISK:
+		return value & 0xFFFF, 2, reason
+	case token.TYPEBOOL:
+		return boolean(value&mask(size) != 0), 1, reason
+	case token.TYPEINT8:
+		return signExtend(value & 0xFF), floor, reason
+	default:
+		return value & 0xFF, floor, reason
+	}
+}
+
+//signed checks if the value of an expression is an int8, whose comparisons and shifts take into account its sign
+func signed(node *ast.Node, scope *symboltable.Scope) bool {
+	switch node.Value.Type {
+	case token.IDENT:
+		symbol, ok := scope.Symbols[node.Value.Literal]
+		return ok && symbol.IsConstant && symboltable.IsInt8(symbol.DataType)
+	case token.RPAREN:
+		if node.Children[0].Value.Type == token.IDENT {
+			return false
+		}
+		if len(node.Children) == 2 {
+			return node.Children[0].Value.Type == token.TYPEINT8
+		}
+	case token.LTLT, token.GTGT:
+		return signed(node.Children[0], scope)
+	case token.EQEQ, token.NOTEQ, token.LT, token.LTEQ, token.GT, token.GTEQ, token.LAND, token.LOR, token.BANG:
+		return false
+	}
+	for _, child := range node.Children {
+		if signed(child, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+//signExtend returns the value of a byte read as an int8 in two's complement
+func signExtend(value int) int {
+	if value&0x80 != 0 {
+		return value | ^0xFF
+	}
+	return value
+}
+
+//mask returns the bits a value of a given size in bytes can hold
+func mask(size int) int {
+	if size == 2 {
+		return 0xFFFF
+	}
+	return 0xFF
+}
+
+//operate applies a binary operator to two values, returns the reason why the operation can't be done at compile time if needed
+func operate(operator token.Type, left int, right int) (int, Reason) {
+	switch operator {
+	case token.PLUS:
+		return left + right, Known
+	case token.MINUS:
+		return left - right, Known
+	case token.ASTERISK:
+		return left * right, Known
+	case token.SLASH:
+		if right == 0 {
+			return 0, DivisionByZero
+		}
+		return left / right, Known
+	case token.PERCENT:
+		if right == 0 {
+			return 0, DivisionByZero
+		}
+		return left % right, Known
+	case token.AND, token.LAND:
+		return left & right, Known
+	case token.OR, token.LOR:
+		return left | right, Known
+	case token.XOR:
+		return left ^ right, Known
+	case token.EQEQ:
+		return boolean(left == right), Known
+	case token.NOTEQ:
+		return boolean(left != right), Known
+	case token.LT:
+		return boolean(left < right), Known
+	case token.LTEQ:
+		return boolean(left <= right), Known
+	case token.GT:
+		return boolean(left > right), Known
+	case token.GTEQ:
+		return boolean(left >= right), Known
+	default:
+		return 0, Unknown
+	}
+}
+
+func boolean(condition bool) int {
+	if condition {
+		return True
+	}
+	return False
+}
+
+//SpriteRows returns the rows of a sprite literal from top to bottom
+func SpriteRows(sprite *ast.Node) []string {
+	rows := make([]string, 0)
+	row := sprite.Children[0].Children[0] //the first row is the child of the block
+	rows = append(rows, row.Value.Literal)
+	for len(row.Children) != 0 {
+		row = row.Children[0]
+		rows = append(rows, row.Value.Literal)
+	}
+	return rows
+}
+
+//CaseValues returns the values of a case clause of a switch, which are chained by commas
+func CaseValues(clause *ast.Node) []*ast.Node {
+	return chained(clause.Children[0])
+}
+
+//chained returns the nodes chained by commas from a given node, which is a comma or the only node of the chain
+func chained(value *ast.Node) []*ast.Node {
+	values := make([]*ast.Node, 0)
+	for value.Value.Type == token.COMMA {
+		values = append(values, value.Children[0])
+		value = value.Children[1]
+	}
+	return append(values, value)
+}
+
+//EnumValues returns the values of an enum declaration, which are chained by commas in its block.
+//A value with an explicit byte is an "=", whose left child is its identifier
+func EnumValues(block *ast.Node) []*ast.Node {
+	return chained(block.Children[0])
+}
+
+//SpriteValues returns a byte for each row of a sprite literal, in which the pixels written as "#" are the bits set to 1,
+//starting from the most significant one
+func SpriteValues(sprite *ast.Node) []byte {
+	values := make([]byte, 0)
+	for _, row := range SpriteRows(sprite) {
+		value := byte(0)
+		for i, pixel := range row {
+			if pixel == '#' {
+				value |= 0x80 >> i
+			}
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+//Values computes the values of a constant expression, of a sprite literal, or of an array literal whose elements are
+//constant expressions, in the order they are saved in memory as the given data type. Each value takes the size of the data
+//type of its element, and words are saved with their high bits first.
+//It returns false if any of them can't be computed at compile time
+func Values(node *ast.Node, scope *symboltable.Scope, datatype interface{}) ([]byte, bool) {
+	if node.Value.Type == token.SPRITE {
+		return SpriteValues(node), true
+	}
+	if node.Value.Type != token.RBRACE {
+		value, ok := Evaluate(node, scope)
+		if symboltable.GetSize(datatype) == 2 {
+			return []byte{byte(value >> 8), byte(value)}, ok
+		}
+		return []byte{byte(value)}, ok
+	}
+	of := datatype.(symboltable.Array).Of
+	values := make([]byte, 0)
+	element := node.Children[0] //element = comma, expression or array literal
+	for element.Value.Type == token.COMMA {
+		elementValues, ok := Values(element.Children[0], scope, of)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, elementValues...)
+		element = element.Children[1]
+	}
+	elementValues, ok := Values(element, scope, of)
+	if !ok {
+		return nil, false
+	}
+	return append(values, elementValues...), true
+}
@@ -0,0 +1,324 @@
+package constant
+
+import (
+	"github.com/NoetherianRing/c8-compiler/ast"
+	"github.com/NoetherianRing/c8-compiler/symboltable"
+	"github.com/NoetherianRing/c8-compiler/token"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func number(value int) *ast.Node {
+	return ast.NewNode(token.NewToken(token.BYTE, strconv.Itoa(value), 0))
+}
+
+func name(identifier string) *ast.Node {
+	return ast.NewNode(token.NewToken(token.IDENT, identifier, 0))
+}
+
+func operation(operator token.Type, operands ...*ast.Node) *ast.Node {
+	node := ast.NewNode(token.NewToken(operator, string(operator), 0))
+	for _, operand := range operands {
+		node.AddChild(operand)
+	}
+	return node
+}
+
+func TestCompute(t *testing.T) {
+	type cases struct {
+		description    string
+		expression     *ast.Node
+		size           int
+		expectedValue  int
+		expectedSize   int
+		expectedReason Reason
+	}
+	scope := symboltable.CreateGlobalScope()
+	scope.AddConstant("K", symboltable.NewByte(), 16)
+	scope.AddConstant("W", symboltable.NewWord(), 0xFFFF)
+	scope.AddConstant("N", symboltable.NewInt8(), 0xFF) //-1
+	scope.AddSymbol("v", symboltable.NewByte())
+
+	testCases := []cases{
+		{
+			description:    "2 + 3",
+			expression:     operation(token.PLUS, number(2), number(3)),
+			size:           1,
+			expectedValue:  5,
+			expectedSize:   1,
+			expectedReason: Known,
+		},
+		{
+			description:    "255 + 1 wraps around as a byte",
+			expression:     operation(token.PLUS, number(255), number(1)),
+			size:           1,
+			expectedValue:  0,
+			expectedSize:   1,
+			expectedReason: Overflow,
+		},
+		{
+			description:    "255 + 1 saved in a word",
+			expression:     operation(token.PLUS, number(255), number(1)),
+			size:           2,
+			expectedValue:  256,
+			expectedSize:   2,
+			expectedReason: Known,
+		},
+		{
+			description:    "200 + 100 saved in a word",
+			expression:     operation(token.PLUS, number(200), number(100)),
+			size:           2,
+			expectedValue:  300,
+			expectedSize:   2,
+			expectedReason: Known,
+		},
+		{
+			description:    "K << 4 saved in a word",
+			expression:     operation(token.LTLT, name("K"), number(4)),
+			size:           2,
+			expectedValue:  256,
+			expectedSize:   2,
+			expectedReason: Known,
+		},
+		{
+			description:    "K << 4",
+			expression:     operation(token.LTLT, name("K"), number(4)),
+			size:           1,
+			expectedValue:  0,
+			expectedSize:   1,
+			expectedReason: Overflow,
+		},
+		{
+			description:    "W + 1",
+			expression:     operation(token.PLUS, name("W"), number(1)),
+			size:           1,
+			expectedValue:  0,
+			expectedSize:   2,
+			expectedReason: Overflow,
+		},
+		{
+			description:    "300 - 45 is a word",
+			expression:     operation(token.MINUS, number(300), number(45)),
+			size:           1,
+			expectedValue:  255,
+			expectedSize:   2,
+			expectedReason: Known,
+		},
+		{
+			description:    "W >> 8 is a word",
+			expression:     operation(token.GTGT, name("W"), number(8)),
+			size:           1,
+			expectedValue:  0xFF,
+			expectedSize:   2,
+			expectedReason: Known,
+		},
+		{
+			description:    "1 - 3 + 5",
+			expression:     operation(token.PLUS, operation(token.MINUS, number(1), number(3)), number(5)),
+			size:           1,
+			expectedValue:  3,
+			expectedSize:   1,
+			expectedReason: Known,
+		},
+		{
+			description:    "1 - 3 is 254",
+			expression:     operation(token.MINUS, number(1), number(3)),
+			size:           1,
+			expectedValue:  254,
+			expectedSize:   1,
+			expectedReason: Known,
+		},
+		{
+			description:    "-1 + 3",
+			expression:     operation(token.PLUS, operation(token.MINUS, number(1)), number(3)),
+			size:           1,
+			expectedValue:  2,
+			expectedSize:   1,
+			expectedReason: Known,
+		},
+		{
+			description:    "-2 is 254",
+			expression:     operation(token.MINUS, number(2)),
+			size:           1,
+			expectedValue:  254,
+			expectedSize:   1,
+			expectedReason: Known,
+		},
+		{
+			description:    "-2 saved in a word is 65534",
+			expression:     operation(token.MINUS, number(2)),
+			size:           2,
+			expectedValue:  0xFFFE,
+			expectedSize:   2,
+			expectedReason: Known,
+		},
+		{
+			description:    "~0",
+			expression:     operation(token.TILDE, number(0)),
+			size:           1,
+			expectedValue:  255,
+			expectedSize:   1,
+			expectedReason: Known,
+		},
+		{
+			description:    "N - 200, an int8 that doesn't fit",
+			expression:     operation(token.MINUS, name("N"), number(200)),
+			size:           1,
+			expectedValue:  55,
+			expectedSize:   1,
+			expectedReason: Overflow,
+		},
+		{
+			description:    "N >> 1 keeps the sign",
+			expression:     operation(token.GTGT, name("N"), number(1)),
+			size:           1,
+			expectedValue:  0xFF,
+			expectedSize:   1,
+			expectedReason: Known,
+		},
+		{
+			description:    "N < 0",
+			expression:     operation(token.LT, name("N"), number(0)),
+			size:           1,
+			expectedValue:  True,
+			expectedSize:   1,
+			expectedReason: Known,
+		},
+		{
+			description:    "(2 + 3) * 4",
+			expression:     operation(token.ASTERISK, operation(token.RPAREN, operation(token.PLUS, number(2), number(3))), number(4)),
+			size:           1,
+			expectedValue:  20,
+			expectedSize:   1,
+			expectedReason: Known,
+		},
+		{
+			description:    "10 / 0",
+			expression:     operation(token.SLASH, number(10), number(0)),
+			size:           1,
+			expectedValue:  0,
+			expectedSize:   0,
+			expectedReason: DivisionByZero,
+		},
+		{
+			description:    "(K - 16) % 0",
+			expression:     operation(token.PERCENT, operation(token.MINUS, name("K"), number(16)), number(0)),
+			size:           1,
+			expectedValue:  0,
+			expectedSize:   0,
+			expectedReason: DivisionByZero,
+		},
+		{
+			description:    "v + 1",
+			expression:     operation(token.PLUS, name("v"), number(1)),
+			size:           1,
+			expectedValue:  0,
+			expectedSize:   0,
+			expectedReason: Unknown,
+		},
+		{
+			description:    "v / 0",
+			expression:     operation(token.SLASH, name("v"), number(0)),
+			size:           1,
+			expectedValue:  0,
+			expectedSize:   0,
+			expectedReason: Unknown,
+		},
+	}
+
+	for _, scenario := range testCases {
+		value, size, reason := Compute(scenario.expression, scope, scenario.size)
+		assert.Equal(t, scenario.expectedReason, reason, scenario.description)
+		assert.Equal(t, scenario.expectedValue, value, scenario.description)
+		assert.Equal(t, scenario.expectedSize, size, scenario.description)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	type cases struct {
+		description   string
+		expression    *ast.Node
+		expectedValue int
+		expectedOk    bool
+	}
+	scope := symboltable.CreateGlobalScope()
+	scope.AddSymbol("v", symboltable.NewByte())
+
+	testCases := []cases{
+		{
+			description:   "200 + 100 wraps around as a register does",
+			expression:    operation(token.PLUS, number(200), number(100)),
+			expectedValue: 44,
+			expectedOk:    true,
+		},
+		{
+			description:   "7 / 2",
+			expression:    operation(token.SLASH, number(7), number(2)),
+			expectedValue: 3,
+			expectedOk:    true,
+		},
+		{
+			description:   "10 / 0",
+			expression:    operation(token.SLASH, number(10), number(0)),
+			expectedValue: 0,
+			expectedOk:    false,
+		},
+		{
+			description:   "v",
+			expression:    name("v"),
+			expectedValue: 0,
+			expectedOk:    false,
+		},
+	}
+
+	for _, scenario := range testCases {
+		value, ok := Evaluate(scenario.expression, scope)
+		assert.Equal(t, scenario.expectedOk, ok, scenario.description)
+		assert.Equal(t, scenario.expectedValue, value, scenario.description)
+	}
+}
+
+func TestExact(t *testing.T) {
+	type cases struct {
+		description    string
+		expression     *ast.Node
+		expectedValue  int
+		expectedReason Reason
+	}
+	scope := symboltable.CreateGlobalScope()
+	scope.AddConstant("N", symboltable.NewInt8(), 0xFF) //-1
+
+	testCases := []cases{
+		{
+			description:    "-128 is below zero",
+			expression:     operation(token.MINUS, number(128)),
+			expectedValue:  -128,
+			expectedReason: Known,
+		},
+		{
+			description:    "N - 2",
+			expression:     operation(token.MINUS, name("N"), number(2)),
+			expectedValue:  -3,
+			expectedReason: Known,
+		},
+		{
+			description:    "200 isn't wrapped around",
+			expression:     number(200),
+			expectedValue:  200,
+			expectedReason: Known,
+		},
+		{
+			description:    "-129 doesn't fit in a byte, so it wraps around",
+			expression:     operation(token.MINUS, number(129)),
+			expectedValue:  127,
+			expectedReason: Overflow,
+		},
+	}
+
+	for _, scenario := range testCases {
+		value, reason := Exact(scenario.expression, scope)
+		assert.Equal(t, scenario.expectedReason, reason, scenario.description)
+		assert.Equal(t, scenario.expectedValue, value, scenario.description)
+	}
+}
@@ -8,6 +8,7 @@ type Stack struct {
 type Reference struct {
 	identifier      string
 	positionInStack int
+	isIndirect      bool //true if the stack saves the address of the variable instead of its value, as with array params
 }
 
 func NewStackReferences() *Stack {
@@ -26,7 +27,11 @@ func (references *Stack) AddSubReferences() {
 }
 
 func (references *Stack) AddReference(ident string, positionStack int) {
-	references.References[ident] = &Reference{ident, positionStack}
+	references.References[ident] = &Reference{ident, positionStack, false}
+}
+
+func (references *Stack) AddIndirectReference(ident string, positionStack int) {
+	references.References[ident] = &Reference{ident, positionStack, true}
 }
 
 func (references *Stack) GetReference(ident string) (*Reference, bool) {
@@ -1,10 +1,65 @@
 package emitter
 
+import "github.com/NoetherianRing/c8-compiler/ast"
+
 type FunctionCtx struct {
-	registerHandler *RegisterHandler
-	stack           *Stack
+	registerHandler     *RegisterHandler
+	stack               *Stack
+	localsSize          int      //the size in the frame of the params and the local variables of the function
+	returnSize          int      //the size of the value the function returns, 0 if it is a void function
+	callDepth           int      //the amount of calls being translated one inside another (e.g. f(g(x)))
+	maxCallDepth        int      //the highest callDepth reached, it gives the amount of register backups the frame needs
+	stackPointerPatches []*StackPointerPatch
+	loops               []*LoopCtx      //the loops that enclose the statement being translated, the innermost last
+	label               string          //the label of the next loop to be translated, empty if it has none
+	loaded              *ast.Node       //the variable of the compound assignation being translated, its value is loaded after the right operand
+	loadedAddress       *ResultRegIndex //the registers in which the address of the loaded variable is
+	loadedSize          int             //the size of the loaded variable
+}
+
+//LoopCtx saves the information a break or a continue needs to jump out of a loop or to its next iteration
+type LoopCtx struct {
+	label        string   //the label of the loop, empty if it has none
+	start        uint16   //the address of the condition of a while, where a continue jumps
+	breakJump    []uint16 //the addresses of the jumps of the breaks, we write them once we know where the loop ends
+	continueJump []uint16 //the addresses of the jumps of the continues of a for, we write them once we know where its step is
+	isFor        bool     //the continues of a for jump to its step, whose address is known once its block is written
+}
+
+//StackPointerPatch saves an address in which we have to write the opcodes that move the stack pointer,
+//and if it moves forward (at the beginning of a call) or back (at the end of a call)
+type StackPointerPatch struct {
+	address uint16
+	forward bool
+}
+
+func NewCtxFunction(registerHandler *RegisterHandler, stackReferences *Stack, localsSize int, returnSize int) *FunctionCtx {
+	return &FunctionCtx{
+		registerHandler:     registerHandler,
+		stack:               stackReferences,
+		localsSize:          localsSize,
+		returnSize:          returnSize,
+		callDepth:           0,
+		maxCallDepth:        0,
+		stackPointerPatches: make([]*StackPointerPatch, 0),
+		loops:               make([]*LoopCtx, 0),
+		label:               "",
+	}
+}
+
+//frameSize returns the size of the frame of the function: its params and local variables, followed by a backup
+//section for each level of nested calls
+func (functionCtx *FunctionCtx) frameSize() int {
+	return functionCtx.localsSize + functionCtx.maxCallDepth*SizeCallBackup
 }
 
-func NewCtxFunction(registerHandler *RegisterHandler, stackReferences *Stack) *FunctionCtx {
-	return &FunctionCtx{registerHandler, stackReferences}
+//findLoop returns the innermost loop with a given label, or just the innermost loop if the label is empty
+func (functionCtx *FunctionCtx) findLoop(label string) *LoopCtx {
+	for i := len(functionCtx.loops) - 1; i >= 0; i-- {
+		loop := functionCtx.loops[i]
+		if label == "" || loop.label == label {
+			return loop
+		}
+	}
+	return nil
 }
@@ -3,7 +3,9 @@ package emitter
 import (
 	"errors"
 	"github.com/NoetherianRing/c8-compiler/ast"
+	"github.com/NoetherianRing/c8-compiler/constant"
 	"github.com/NoetherianRing/c8-compiler/errorhandler"
+	"github.com/NoetherianRing/c8-compiler/semanticAnalyzer"
 	"github.com/NoetherianRing/c8-compiler/symboltable"
 	"github.com/NoetherianRing/c8-compiler/token"
 	"strconv"
@@ -11,7 +13,7 @@ import (
 
 type Emitter struct {
 	currentAddress     uint16
-	offset             int               //we use this field to know the last address in which we save a variable in memory
+	offset             int               //we use this field to know the last position of the frame in which we save a variable
 	globalVariables    map[string]uint16 //we save in globalVariables the address in which each global variable is stored
 	scope              *symboltable.Scope
 	ctxNode            *ast.Node
@@ -20,14 +22,26 @@ type Emitter struct {
 	translateOperation map[token.Type]func(function *FunctionCtx) (*ResultRegIndex, error)
 	functions          map[string]uint16 //we save in functions the address in which each function is stored
 	lastIndexSubScope  int               //in the context of a scope, lastIndexSubScope tells the numbers of sub-scopes already written in machineCode
+	callPatches        []*CallPatch      //the calls whose opcode we write once all the functions are in memory
+	datatypeFactory    *semanticAnalyzer.DataTypeFactory
+	spillArea          uint16 //the address in which the registers are saved around a block copy or comparison, 0 until it is written
 
 }
 
+//CallPatch saves an address in which we have to write the call to a function,
+//because a function can be called before the address in which it is stored is known
+type CallPatch struct {
+	address  uint16
+	function string
+}
+
 func NewEmitter(tree *ast.SyntaxTree, scope *symboltable.Scope) *Emitter {
 	emitter := new(Emitter)
 
 	emitter.globalVariables = make(map[string]uint16)
 	emitter.functions = make(map[string]uint16)
+	emitter.callPatches = make([]*CallPatch, 0)
+	emitter.datatypeFactory = semanticAnalyzer.NewDataTypeFactory()
 	emitter.scope = scope
 	emitter.lastIndexSubScope = 0
 	emitter.ctxNode = tree.Head
@@ -37,17 +51,38 @@ func NewEmitter(tree *ast.SyntaxTree, scope *symboltable.Scope) *Emitter {
 	emitter.translateStatement[token.IF] = emitter._if
 	emitter.translateStatement[token.ELSE] = emitter._else
 	emitter.translateStatement[token.WHILE] = emitter._while
+	emitter.translateStatement[token.FOR] = emitter._for
 	emitter.translateStatement[token.EQ] = emitter.assign
+	emitter.translateStatement[token.PLUSEQ] = emitter.compoundAssign
+	emitter.translateStatement[token.MINUSEQ] = emitter.compoundAssign
+	emitter.translateStatement[token.ASTERISKEQ] = emitter.compoundAssign
+	emitter.translateStatement[token.SLASHEQ] = emitter.compoundAssign
+	emitter.translateStatement[token.PERCENTEQ] = emitter.compoundAssign
+	emitter.translateStatement[token.ANDEQ] = emitter.compoundAssign
+	emitter.translateStatement[token.OREQ] = emitter.compoundAssign
+	emitter.translateStatement[token.XOREQ] = emitter.compoundAssign
+	emitter.translateStatement[token.LTLTEQ] = emitter.compoundAssign
+	emitter.translateStatement[token.GTGTEQ] = emitter.compoundAssign
+	emitter.translateStatement[token.PLUSPLUS] = emitter.compoundAssign
+	emitter.translateStatement[token.MINUSMINUS] = emitter.compoundAssign
+	emitter.translateStatement[token.LET] = emitter.initialization
+	emitter.translateStatement[token.CONST] = emitter._const
 	emitter.translateStatement[token.RPAREN] = emitter.voidCall
 	emitter.translateStatement[token.RETURN] = emitter._return
+	emitter.translateStatement[token.SWITCH] = emitter._switch
+	emitter.translateStatement[token.COLON] = emitter.label
+	emitter.translateStatement[token.BREAK] = emitter._break
+	emitter.translateStatement[token.CONTINUE] = emitter._continue
 
 	emitter.translateOperation = make(map[token.Type]func(*FunctionCtx) (*ResultRegIndex, error))
 
 	emitter.translateOperation[token.DOLLAR] = emitter.address
 	emitter.translateOperation[token.RPAREN] = emitter.parenthesis
 	emitter.translateOperation[token.RBRACKET] = emitter.bracket
+	emitter.translateOperation[token.DOT] = emitter.field
 	emitter.translateOperation[token.PLUS] = emitter.sum
-	emitter.translateOperation[token.MINUS] = emitter.subtraction
+	emitter.translateOperation[token.MINUS] = emitter.minus
+	emitter.translateOperation[token.TILDE] = emitter.complement
 	emitter.translateOperation[token.ASTERISK] = emitter.asterisk
 	emitter.translateOperation[token.PERCENT] = emitter.mod
 	emitter.translateOperation[token.SLASH] = emitter.division
@@ -61,6 +96,7 @@ func NewEmitter(tree *ast.SyntaxTree, scope *symboltable.Scope) *Emitter {
 	emitter.translateOperation[token.EQEQ] = emitter.eqeq
 	emitter.translateOperation[token.NOTEQ] = emitter.noteq
 	emitter.translateOperation[token.BANG] = emitter.not
+	emitter.translateOperation[token.QUESTION] = emitter.conditional
 	emitter.translateOperation[token.LT] = emitter.ltgt
 	emitter.translateOperation[token.GT] = emitter.ltgt
 	emitter.translateOperation[token.GTEQ] = emitter.ltgteq
@@ -68,6 +104,12 @@ func NewEmitter(tree *ast.SyntaxTree, scope *symboltable.Scope) *Emitter {
 	emitter.translateOperation[token.BOOL] = emitter.boolean
 	emitter.translateOperation[token.BYTE] = emitter._byte
 	emitter.translateOperation[token.IDENT] = emitter.ident
+	emitter.translateOperation[token.SPRITE] = emitter.sprite
+
+	//an operation whose value is known at compile time is replaced by its value
+	for tokenType, translate := range emitter.translateOperation {
+		emitter.translateOperation[tokenType] = emitter.foldConstant(translate)
+	}
 
 	emitter.currentAddress = AddressGlobalSection
 	return emitter
@@ -113,7 +155,15 @@ func (emitter *Emitter) Start() ([]byte, error) {
 	emitter.scope = mainScope
 	emitter.ctxNode = block
 
-	//The stack section will start in the last available address, which is saved in the vD and vE registers
+	//now that we know the address of all the functions, we write the calls
+	for _, patch := range emitter.callPatches {
+		callFunction := I2NNN(emitter.functions[patch.function])
+		emitter.machineCode[patch.address] = callFunction[0]
+		emitter.machineCode[patch.address+1] = callFunction[1]
+	}
+
+	//The stack section will start in the last available address, which is saved in the vD and vE registers.
+	//During the execution vD and vE point to the frame of the function being executed
 	vD := byte((emitter.currentAddress & 0xFF00) >> 8)
 	VE := byte(emitter.currentAddress & 0x00FF)
 	x := byte(RegisterStackAddress1)
@@ -362,9 +412,9 @@ func (emitter *Emitter) fn() error {
 	const ARG = 1
 	const BLOCK = 3
 	emitter.lastIndexSubScope = 0
+	emitter.offset = 0 //the positions of the params and variables are relative to the frame of the function
 	registerHandler := NewRegisterHandler()
 
-
 	//we store the address in which the function is saved in the map of functions
 	functionName := emitter.ctxNode.Children[IDENT].Value.Literal
 	emitter.functions[functionName] = emitter.currentAddress //the function starts at the current address
@@ -417,13 +467,14 @@ func (emitter *Emitter) fn() error {
 
 	emitter.ctxNode = fn
 
-	ctxFunction := NewCtxFunction(registerHandler, ctxReferences)
+	returnSize := symboltable.GetSize(mainScope.Symbols[functionName].DataType.(symboltable.Function).Return)
+	ctxFunction := NewCtxFunction(registerHandler, ctxReferences, emitter.offset, returnSize)
 
 	//we write the rest of the statements in memory
 	for _, child := range fn.Children[BLOCK].Children {
 
 		emitter.ctxNode = child
-		//we jump let stmts because we already save them
+		//let statements were already declared, so we only write their initialization
 		translateStmt, ok := emitter.translateStatement[emitter.ctxNode.Value.Type]
 		if ok {
 			err := translateStmt(ctxFunction)
@@ -434,6 +485,20 @@ func (emitter *Emitter) fn() error {
 
 	}
 
+	//void functions can end without a return statement, so we return after the last statement
+	statements := fn.Children[BLOCK].Children
+	if len(statements) == 0 || statements[len(statements)-1].Value.Type != token.RETURN {
+		err = emitter.saveOpcode(I00EE())
+		if err != nil {
+			return err
+		}
+	}
+
+	//now that we know the size of the frame, we write the opcodes that move the stack pointer in each call
+	for _, patch := range ctxFunction.stackPointerPatches {
+		emitter.writeMoveStackPointer(patch, ctxFunction.frameSize())
+	}
+
 	emitter.scope = mainScope
 	return nil
 }
@@ -444,9 +509,15 @@ func (emitter *Emitter) saveParamInStack(ctxReferences *Stack, iParam int, iReg
 	const IDENT = 0
 	//first we declare them in the stack
 	paramIdent := emitter.ctxNode.Children[IDENT].Value.Literal
-	err := emitter.let(ctxReferences)
-	if err != nil {
-		return err
+	var err error
+	if symboltable.IsAnArray(emitter.scope.Symbols[paramIdent].DataType) {
+		//an array is passed as the address of its first element, which the stack saves instead of the array
+		ctxReferences.AddIndirectReference(paramIdent, emitter.offset)
+	} else {
+		err = emitter.let(ctxReferences)
+		if err != nil {
+			return err
+		}
 	}
 	//then we set its value
 
@@ -495,8 +566,20 @@ func (emitter *Emitter) globalVariableDeclaration() error {
 	size := symboltable.GetSize(symbol.DataType)
 	emitter.globalVariables[ident] = emitter.currentAddress
 
+	//if the variable is initialized we write its value, if not we fill it with zeros
+	values := make([]byte, size)
+	initialization, ok := initializationOf(let)
+	if symbol.Data != nil {
+		values = symbol.Data
+	} else if ok {
+		values, ok = constant.Values(initialization.Children[0], emitter.scope, symbol.DataType)
+		if !ok {
+			return errors.New(errorhandler.UnexpectedCompilerError())
+		}
+	}
+
 	for i := 0; i < size; i++ {
-		emitter.machineCode[emitter.currentAddress] = 0
+		emitter.machineCode[emitter.currentAddress] = values[i]
 		err := emitter.moveCurrentAddress()
 		if err != nil {
 			return err
@@ -506,6 +589,120 @@ func (emitter *Emitter) globalVariableDeclaration() error {
 
 }
 
+//initializationOf returns the initialization of a declaration, which is always its last child, and if it has one
+func initializationOf(let *ast.Node) (*ast.Node, bool) {
+	last := let.Children[len(let.Children)-1]
+	return last, last.Value.Type == token.EQ
+}
+
+//initialization writes the value of an initialized local variable in the stack when its declaration is executed.
+//The values of array literals are saved between the opcodes and copied to the frame in blocks,
+//the rest of the values are saved as in an assignation
+func (emitter *Emitter) initialization(functionCtx *FunctionCtx) error {
+	const IDENT = 0
+	initialization, ok := initializationOf(emitter.ctxNode)
+	if !ok {
+		return nil
+	}
+	identNode := emitter.ctxNode.Children[IDENT]
+	value := initialization.Children[0]
+	if value.Value.Type != token.RBRACE && value.Value.Type != token.SPRITE {
+		emitter.ctxNode = value
+		valueToSaveRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
+		if err != nil {
+			return err
+		}
+		emitter.ctxNode = identNode
+		return emitter.saveInVariable(functionCtx, valueToSaveRegIndex)
+	}
+
+	reference, ok := functionCtx.stack.GetReference(identNode.Value.Literal)
+	if !ok {
+		return errors.New(errorhandler.UnexpectedCompilerError())
+	}
+	symbol, ok := emitter.scope.Symbols[identNode.Value.Literal]
+	if !ok {
+		return errors.New(errorhandler.UnexpectedCompilerError())
+	}
+	values, ok := constant.Values(value, emitter.scope, symbol.DataType)
+	if !ok {
+		return errors.New(errorhandler.UnexpectedCompilerError())
+	}
+	address, err := emitter.saveData(values)
+	if err != nil {
+		return err
+	}
+	return emitter.copyData(address, reference.positionInStack, len(values))
+}
+
+//_const doesn't write anything, because constants are replaced by their values wherever they are used
+func (emitter *Emitter) _const(functionCtx *FunctionCtx) error {
+	return nil
+}
+
+//saveData writes a sequence of bytes between the opcodes, after a jump that skips them.
+//Returns the address of the first byte and an error if needed
+func (emitter *Emitter) saveData(values []byte) (uint16, error) {
+	//because we don't know yet the address after the data, we save the current address to write the jump later
+	jump := emitter.currentAddress
+	err := emitter.moveCurrentAddress()
+	if err != nil {
+		return 0, err
+	}
+	err = emitter.moveCurrentAddress()
+	if err != nil {
+		return 0, err
+	}
+
+	address := emitter.currentAddress
+	for _, value := range values {
+		emitter.machineCode[emitter.currentAddress] = value
+		err = emitter.moveCurrentAddress()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	jumpData := I1NNN(emitter.currentAddress)
+	emitter.machineCode[jump] = jumpData[0]
+	emitter.machineCode[jump+1] = jumpData[1]
+	return address, nil
+}
+
+//copyData copies "size" bytes from an address to the position "offset" of the frame.
+//Because FX65 and FX55 read and write registers from v0, we copy them in blocks of as many registers as we can use.
+//Returns an error if needed
+func (emitter *Emitter) copyData(address uint16, offset int, size int) error {
+	aux := byte(AmountOfRegistersToOperate - 1) //we use the last register to move I, so it can't be part of a block
+	for copied := 0; copied < size; copied += int(aux) {
+		block := size - copied
+		if block > int(aux) {
+			block = int(aux)
+		}
+		err := emitter.saveOpcode(IANNN(address + uint16(copied))) //I = address of the block
+		if err != nil {
+			return err
+		}
+		err = emitter.saveOpcode(IFX65(byte(block - 1)))
+		if err != nil {
+			return err
+		}
+		err = emitter.saveOpcode(I9XY1(RegisterStackAddress1, RegisterStackAddress2)) // I = stack address
+		if err != nil {
+			return err
+		}
+		err = emitter.saveFX1ESafely(aux, offset+copied) //I = I + offset
+		if err != nil {
+			return err
+		}
+		err = emitter.saveOpcode(IFX55(byte(block - 1)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 //moveCurrentAddress moves the current address by one, and if it's out of bounds of the memory it return a error
 func (emitter *Emitter) moveCurrentAddress() error {
 	emitter.currentAddress++
@@ -525,6 +722,10 @@ func (emitter *Emitter) declareInStack(ctxReferences *Stack) error {
 	iSubScope := 0
 
 	for _, child := range emitter.ctxNode.Children {
+		//a labeled loop declares its variables as the loop itself does
+		if child.Value.Type == token.COLON {
+			child = child.Children[1]
+		}
 		switch child.Value.Type {
 		case token.WHILE:
 			emitter.ctxNode = child.Children[1]
@@ -540,6 +741,31 @@ func (emitter *Emitter) declareInStack(ctxReferences *Stack) error {
 				return err
 			}
 
+		case token.FOR:
+			//the variable of a for is declared in the scope of the loop, which encloses the scope of its block
+			const INIT = 0
+			const BLOCK = 3
+			emitter.scope = emitter.scope.SubScopes[iSubScope]
+			ctxReferences.AddSubReferences()
+			loopReferences := ctxReferences.SubReferences[iSubScope]
+			iSubScope++
+			var err error
+			if child.Children[INIT].Value.Type == token.LET {
+				emitter.ctxNode = child.Children[INIT]
+				err = emitter.let(loopReferences)
+			}
+			if err == nil {
+				emitter.ctxNode = child.Children[BLOCK]
+				emitter.scope = emitter.scope.SubScopes[0]
+				loopReferences.AddSubReferences()
+				err = emitter.declareInStack(loopReferences.SubReferences[0])
+			}
+			emitter.ctxNode = backupCtxNode
+			emitter.scope = backupScope
+			if err != nil {
+				return err
+			}
+
 		case token.IF:
 			emitter.ctxNode = child.Children[1]
 			emitter.scope = emitter.scope.SubScopes[iSubScope]
@@ -564,12 +790,26 @@ func (emitter *Emitter) declareInStack(ctxReferences *Stack) error {
 					return err
 				}
 			}
+		case token.SWITCH:
+			//the block of each clause is its last child
+			for _, clause := range child.Children[1].Children {
+				emitter.ctxNode = clause.Children[len(clause.Children)-1]
+				emitter.scope = emitter.scope.SubScopes[iSubScope]
+				ctxReferences.AddSubReferences()
+				err := emitter.declareInStack(ctxReferences.SubReferences[iSubScope])
+				iSubScope++
+				emitter.ctxNode = backupCtxNode
+				emitter.scope = backupScope
+				if err != nil {
+					return err
+				}
+			}
 		case token.LET:
 			backup := emitter.ctxNode
 			emitter.ctxNode = child
 			err := emitter.let(ctxReferences)
 			if err != nil {
-				return nil
+				return err
 			}
 			emitter.ctxNode = backup
 		}
@@ -596,15 +836,14 @@ func (emitter *Emitter) let(ctxReferences *Stack) error {
 		if err != nil {
 			return err
 		}
-		err = emitter.saveOpcode(I6XKK(0, byte(emitter.offset))) //v0 = offset
+		err = emitter.saveFX1ESafely(0, emitter.offset) // I = I + offset
 		if err != nil {
 			return err
 		}
-		err = emitter.saveOpcode(IFX1E(0)) // I = I + V0
+		err = emitter.saveOpcode(IFX55(15))
 		if err != nil {
 			return err
 		}
-		err = emitter.saveOpcode(IFX55(15))
 		size -= 16
 		emitter.offset += 16
 	}
@@ -613,15 +852,14 @@ func (emitter *Emitter) let(ctxReferences *Stack) error {
 		if err != nil {
 			return err
 		}
-		err = emitter.saveOpcode(I6XKK(0, byte(emitter.offset))) //v0 = offset
+		err = emitter.saveFX1ESafely(0, emitter.offset) // I = I + offset
 		if err != nil {
 			return err
 		}
-		err = emitter.saveOpcode(IFX1E(0)) // I = I + V0
+		err = emitter.saveOpcode(IFX55(byte(size - 1)))
 		if err != nil {
 			return err
 		}
-		err = emitter.saveOpcode(IFX55(byte(size - 1)))
 		emitter.offset += size
 	}
 
@@ -634,6 +872,11 @@ func (emitter *Emitter) assign(functionCtx *FunctionCtx) error {
 	const LEFT = 0
 	const RIGHT = 1
 
+	datatype, ok := emitter.dataTypeOf(emitter.ctxNode.Children[LEFT])
+	if ok && symboltable.IsAnArray(datatype) {
+		return emitter.copyArray(functionCtx, symboltable.GetSize(datatype))
+	}
+
 	assignBackup := emitter.ctxNode
 	emitter.ctxNode = emitter.ctxNode.Children[RIGHT]
 	valueToSaveRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
@@ -642,30 +885,138 @@ func (emitter *Emitter) assign(functionCtx *FunctionCtx) error {
 	}
 	emitter.ctxNode = assignBackup
 	emitter.ctxNode = emitter.ctxNode.Children[LEFT]
+	return emitter.saveInVariable(functionCtx, valueToSaveRegIndex)
+}
+
+//copyArray translates the assignation of an array to opcodes and write it in emitter.machineCode. The array is copied in
+//blocks of SizeBlock bytes, loading each block from the right array in every register and saving it in the left one.
+//Returns an error if needed
+func (emitter *Emitter) copyArray(functionCtx *FunctionCtx, size int) error {
+	const LEFT = 0
+	const RIGHT = 1
+	assignBackup := emitter.ctxNode
+	err := emitter.spillRegisters()
+	if err != nil {
+		return err
+	}
+	blocks := (size + SizeBlock - 1) / SizeBlock
+	emitter.ctxNode = assignBackup.Children[RIGHT]
+	fromPatches, err := emitter.reserveBlockAddresses(functionCtx, blocks, SizeBlock)
+	if err != nil {
+		return err
+	}
+	emitter.ctxNode = assignBackup.Children[LEFT]
+	toPatches, err := emitter.reserveBlockAddresses(functionCtx, blocks, SizeBlock)
+	if err != nil {
+		return err
+	}
+	emitter.ctxNode = assignBackup
+
+	for i := 0; i < blocks; i++ {
+		block := byte(size - i*SizeBlock)
+		if block > SizeBlock {
+			block = SizeBlock
+		}
+		err = emitter.writeBlockAddress(fromPatches[i]) //I = address of the block of the right array
+		if err != nil {
+			return err
+		}
+		err = emitter.saveOpcode(IFX65(block - 1))
+		if err != nil {
+			return err
+		}
+		err = emitter.writeBlockAddress(toPatches[i]) //I = address of the block of the left array
+		if err != nil {
+			return err
+		}
+		err = emitter.saveOpcode(IFX55(block - 1))
+		if err != nil {
+			return err
+		}
+	}
+	return emitter.restoreRegisters()
+}
+
+//compoundAssign translates a compound assignation (like x += 2), an increment or a decrement to opcodes and write it
+//in emitter.machineCode. The address of the variable is computed once and kept in two registers, and its value is
+//loaded as the left operand of the operation once the right one is solved, as in x = x + 2. Returns an error if needed
+func (emitter *Emitter) compoundAssign(functionCtx *FunctionCtx) error {
+	const TARGET = 0
+	operation := ast.NewCompoundOperation(emitter.ctxNode)
+	emitter.ctxNode = emitter.ctxNode.Children[TARGET]
+	size, err := emitter.saveVariableAddressInI(functionCtx)
+	if err != nil {
+		return err
+	}
+	addressRegIndex, ok := functionCtx.registerHandler.AllocPointer()
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		return errors.New(errorhandler.TooManyRegisters(line))
+	}
+	err = emitter.saveOpcode(I9XY2(addressRegIndex.highBitsIndex, addressRegIndex.lowBitsIndex))
+	if err != nil {
+		return err
+	}
+	functionCtx.loaded = operation.Children[TARGET]
+	functionCtx.loadedAddress = addressRegIndex
+	functionCtx.loadedSize = size
+
+	emitter.ctxNode = operation
+	resultRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
+	if err != nil {
+		return err
+	}
+	err = emitter.saveOpcode(I9XY1(addressRegIndex.highBitsIndex, addressRegIndex.lowBitsIndex)) //I = address
+	if err != nil {
+		return err
+	}
+	functionCtx.registerHandler.Free(addressRegIndex)
+	return emitter.saveInI(functionCtx, resultRegIndex)
+}
+
+//saveInVariable saves the value of a register (or a pair of registers) in the variable or dereference led by
+//the ctx node, and frees the register. Returns an error if needed
+func (emitter *Emitter) saveInVariable(functionCtx *FunctionCtx, valueToSaveRegIndex *ResultRegIndex) error {
+	size, err := emitter.saveVariableAddressInI(functionCtx)
+	if err != nil {
+		return err
+	}
+	//a byte saved in a word is widened
+	if size == 2 && !valueToSaveRegIndex.isPointer {
+		valueToSaveRegIndex, err = emitter.widen(functionCtx, valueToSaveRegIndex)
+		if err != nil {
+			return err
+		}
+	}
+	return emitter.saveInI(functionCtx, valueToSaveRegIndex)
+}
 
-	//we evaluate if we are assigning to a global references, to a stack references or to a dereference
-	//we save its address in I using v0 and v1 as auxiliary and we save its size
+//saveVariableAddressInI saves in I the address of the variable, dereference or field led by the ctx node, using v0 and v1
+//as auxiliary. Returns the size of the variable and an error if needed
+func (emitter *Emitter) saveVariableAddressInI(functionCtx *FunctionCtx) (int, error) {
+	//we evaluate if it is a field, a global reference, a stack reference or a dereference
+	if emitter.ctxNode.Value.Type == token.DOT {
+		datatype, err := emitter.saveFieldAddressInI(functionCtx)
+		if err != nil {
+			return 0, err
+		}
+		return symboltable.GetSize(datatype), nil
+	}
 	if emitter.ctxNode.Value.Type == token.IDENT {
 		ident := emitter.ctxNode.Value.Literal
 		_, isAGlobalReference := emitter.globalVariables[ident]
 		if isAGlobalReference {
-			_, err = emitter.saveGlobalReferenceAddressInI(0, 1)
-			if err != nil {
-				return err
-			}
-		} else {
-			_, err = emitter.saveStackReferenceAddressInI(0, functionCtx)
-			if err != nil {
-				return err
-			}
-		}
-	} else {
-		_, err = emitter.saveDereferenceAddressInI(functionCtx)
-		if err != nil {
-			return err
+			return emitter.saveGlobalReferenceAddressInI(0, 1)
 		}
+		return emitter.saveStackReferenceAddressInI(0, functionCtx)
 	}
+	return emitter.saveDereferenceAddressInI(functionCtx)
+}
 
+//saveInI saves the value of a register (or a pair of registers) in the address saved in I, and frees the register.
+//Returns an error if needed
+func (emitter *Emitter) saveInI(functionCtx *FunctionCtx, valueToSaveRegIndex *ResultRegIndex) error {
+	var err error
 	//we save in v0 (and v1) the value to save
 	if valueToSaveRegIndex.isPointer {
 		err = emitter.saveOpcode(I8XY0(0, valueToSaveRegIndex.highBitsIndex))
@@ -704,7 +1055,22 @@ func (emitter *Emitter) _return(functionCtx *FunctionCtx) error {
 		if err != nil {
 			return err
 		}
-		err = emitter.saveOpcode(I8XY0(0, returnRegIndex.lowBitsIndex)) //v0 = return value
+		if functionCtx.returnSize == 2 {
+			//a byte returned as a word is widened, and then we set v0 = first 8 bits and v1 = last 8 bits
+			if !returnRegIndex.isPointer {
+				returnRegIndex, err = emitter.widen(functionCtx, returnRegIndex)
+				if err != nil {
+					return err
+				}
+			}
+			err = emitter.saveOpcode(I8XY0(0, returnRegIndex.highBitsIndex))
+			if err != nil {
+				return err
+			}
+			err = emitter.saveOpcode(I8XY0(1, returnRegIndex.lowBitsIndex))
+		} else {
+			err = emitter.saveOpcode(I8XY0(0, returnRegIndex.lowBitsIndex)) //v0 = return value
+		}
 		if err != nil {
 			return err
 		}
@@ -837,6 +1203,9 @@ func (emitter *Emitter) _while(functionCtx *FunctionCtx) error {
 	//we save the initial address to jump in every iteration
 	initial := emitter.currentAddress
 	backup := emitter.ctxNode
+	loop := &LoopCtx{label: functionCtx.label, start: initial, breakJump: make([]uint16, 0)}
+	functionCtx.loops = append(functionCtx.loops, loop)
+	functionCtx.label = ""
 
 	emitter.ctxNode = emitter.ctxNode.Children[CONDITION]
 	resultRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
@@ -872,16 +1241,28 @@ func (emitter *Emitter) _while(functionCtx *FunctionCtx) error {
 		return err
 	}
 
-	//then we write the jump after the condition
+	//then we write the jump after the condition, and the ones of the breaks
 	jumpWhile := I1NNN(emitter.currentAddress)
 
 	emitter.machineCode[lineAfterCondition] = jumpWhile[0]
 	emitter.machineCode[lineAfterCondition+1] = jumpWhile[1]
+	for _, breakJump := range loop.breakJump {
+		emitter.machineCode[breakJump] = jumpWhile[0]
+		emitter.machineCode[breakJump+1] = jumpWhile[1]
+	}
+	functionCtx.loops = functionCtx.loops[:len(functionCtx.loops)-1]
 	return nil
 }
 
-//block translates a block  to opcodes and write it in emitter.machineCode, it also handle the scope
-func (emitter *Emitter) block(functionCtx *FunctionCtx) error {
+//_for translates the for statement to opcodes and write it in emitter.machineCode. The initialization is written once
+//before the loop, the rest of the loop is written as a while whose step is placed after the block, before the jump to
+//the condition. The variable of the loop lives in the scope of the loop, which encloses the scope of the block
+func (emitter *Emitter) _for(functionCtx *FunctionCtx) error {
+	const INIT = 0
+	const CONDITION = 1
+	const STEP = 2
+	const BLOCK = 3
+
 	lastIndexSubScopeBackup := emitter.lastIndexSubScope
 	scopeBackup := emitter.scope
 	addressesBackup := functionCtx.stack
@@ -889,630 +1270,1470 @@ func (emitter *Emitter) block(functionCtx *FunctionCtx) error {
 	functionCtx.stack = functionCtx.stack.SubReferences[emitter.lastIndexSubScope]
 	emitter.lastIndexSubScope = 0
 
-	block := emitter.ctxNode
-
-	for _, child := range block.Children {
-		//we jump let statements because they were already executed in the declaration of a function
-		if child.Value.Type != token.LET {
-			emitter.ctxNode = child
-			err := emitter.translateStatement[emitter.ctxNode.Value.Type](functionCtx)
-			if err != nil {
-				return err
-			}
-		}
+	backup := emitter.ctxNode
+	emitter.ctxNode = backup.Children[INIT]
+	err := emitter.translateStatement[emitter.ctxNode.Value.Type](functionCtx)
+	if err != nil {
+		return err
 	}
 
-	emitter.lastIndexSubScope = lastIndexSubScopeBackup + 1
-	emitter.scope = scopeBackup
-	functionCtx.stack = addressesBackup
-	return nil
-}
-
-//bracket analyze save a dereference in registers,
-//returns the index of registers in which the result of the operation was stored and an error if needed
-func (emitter *Emitter) bracket(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	return emitter.saveDereferenceInRegisters(functionCtx)
-}
+	//we save the address of the condition to jump in every iteration
+	initial := emitter.currentAddress
+	loop := &LoopCtx{label: functionCtx.label, breakJump: make([]uint16, 0), continueJump: make([]uint16, 0), isFor: true}
+	functionCtx.loops = append(functionCtx.loops, loop)
+	functionCtx.label = ""
 
-//parenthesis analyze the context of a parenthesis and delegate the operation,
-//returns the index of registers in which the result of the operation was stored and an error if needed
-func (emitter *Emitter) parenthesis(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	if emitter.ctxNode.Children[0].Value.Type == token.IDENT {
-		return emitter.call(functionCtx)
+	emitter.ctxNode = backup.Children[CONDITION]
+	resultRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
+	if err != nil {
+		return err
 	}
-	emitter.ctxNode = emitter.ctxNode.Children[0] //skip node
-	return emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
-}
-
-//voidCall translates a void call to opcodes and write it in emitter.machineCode
-func (emitter *Emitter) voidCall(functionCtx *FunctionCtx) error {
-	_, err := emitter.call(functionCtx)
-	return err
-}
-
-//call translates a function call to opcodes and write it in emitter.machineCode,
-//returns the indexes of registers in which the return value is stored and an error if needed
-func (emitter *Emitter) call(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	const IDENT = 0
-	aux, ok := functionCtx.registerHandler.AllocSimple()
-	if !ok {
-		line := emitter.ctxNode.Value.Line
-		err := errors.New(errorhandler.TooManyRegisters(line))
-		return nil, err
-
+	err = emitter.saveOpcode(I4XKK(resultRegIndex.lowBitsIndex, False)) //if vx = true (vx!=false) we skip the next instruction
+	if err != nil {
+		return err
+	}
+	functionCtx.registerHandler.Free(resultRegIndex)
+	//the next instruction is a jump to the memory address after the for
+	//because we don't know this address yet, we save the current address to write the opcode later
+	lineAfterCondition := emitter.currentAddress
+	err = emitter.moveCurrentAddress()
+	if err != nil {
+		return err
+	}
+	err = emitter.moveCurrentAddress()
+	if err != nil {
+		return err
 	}
-	ident := emitter.ctxNode.Children[IDENT].Value.Literal
 
-	//we first backup all registers of the current function in  the stack
-	err := emitter.backupRegistersInMemory(emitter.offset)
+	emitter.ctxNode = backup.Children[BLOCK]
+	err = emitter.block(functionCtx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	//	backupOffsetStack := emitter.offset
-	emitter.offset += AmountOfRegistersToOperate
-	//then we save the params of the function call in registers
-	err = emitter.saveParamsInRegisters(functionCtx, ident)
+	//the continues jump to the step, which is written after the block
+	jumpStep := I1NNN(emitter.currentAddress)
+	for _, continueJump := range loop.continueJump {
+		emitter.machineCode[continueJump] = jumpStep[0]
+		emitter.machineCode[continueJump+1] = jumpStep[1]
+	}
+	emitter.ctxNode = backup.Children[STEP]
+	err = emitter.translateStatement[emitter.ctxNode.Value.Type](functionCtx)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	err = emitter.saveOpcode(I1NNN(initial)) //after the step, we jump to the address of the condition
+	if err != nil {
+		return err
 	}
 
-	//we call the function
-	fnAddress, _ := emitter.functions[ident]
-	err = emitter.saveOpcode(I2NNN(fnAddress))
+	//then we write the jump after the condition, and the ones of the breaks
+	jumpFor := I1NNN(emitter.currentAddress)
+	emitter.machineCode[lineAfterCondition] = jumpFor[0]
+	emitter.machineCode[lineAfterCondition+1] = jumpFor[1]
+	for _, breakJump := range loop.breakJump {
+		emitter.machineCode[breakJump] = jumpFor[0]
+		emitter.machineCode[breakJump+1] = jumpFor[1]
+	}
+	functionCtx.loops = functionCtx.loops[:len(functionCtx.loops)-1]
+
+	emitter.lastIndexSubScope = lastIndexSubScopeBackup + 1
+	emitter.scope = scopeBackup
+	functionCtx.stack = addressesBackup
+	return nil
+}
+
+//_switch translates the switch statement to opcodes and write it in emitter.machineCode.
+//The subject is evaluated once, and then we jump to the block of the clause that handles its value, with a jump table
+//if the values of the cases are dense or with a chain of comparisons if not. After each block we jump to the end of the switch
+func (emitter *Emitter) _switch(functionCtx *FunctionCtx) error {
+	const SUBJECT = 0
+	const CLAUSES = 1
+
+	backup := emitter.ctxNode
+	emitter.ctxNode = backup.Children[SUBJECT]
+	subjectRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	//if it was not a void function, now the return value is in vf.
 
-	//if the function we call was not a void function, then we save in memory a backup of the return value,
-	//because we will need the register v0
-	size := symboltable.GetSize(emitter.scope.Symbols[ident].DataType.(symboltable.Function).Return) //size is always 1
-	if size != 0 {
-		err := emitter.saveOpcode(I9XY1(RegisterStackAddress1, RegisterStackAddress2)) // I = stack address
-		if err != nil {
-			return nil, err
+	//we map each value to the index of the clause that handles it, the values without a case are handled by the default,
+	//or by the end of the switch if it has no default
+	clauses := backup.Children[CLAUSES].Children
+	defaultClause := len(clauses)
+	cases := make(map[int]int)
+	for i, clause := range clauses {
+		if clause.Value.Type == token.DEFAULT {
+			defaultClause = i
+			continue
 		}
-		err = emitter.saveFX1ESafely(aux.lowBitsIndex, emitter.offset) //I = I + offset
-		if err != nil {
-			return nil, err
+		for _, value := range constant.CaseValues(clause) {
+			constantValue, _ := constant.Evaluate(value, emitter.scope)
+			cases[constantValue] = i
 		}
-		emitter.offset += size //size=1
-		err = emitter.saveOpcode(IFX55(0))
+	}
+
+	//because the blocks are written after the comparisons, we save the addresses of the jumps to the block of
+	//each clause to write them later. The last element saves the jumps to the end of the switch
+	jumps := make([][]uint16, len(clauses)+1)
+	if isDense(cases) {
+		err = emitter.jumpTable(subjectRegIndex, cases, defaultClause, jumps)
+	} else {
+		err = emitter.jumpChain(subjectRegIndex, cases, defaultClause, jumps)
+	}
+	if err != nil {
+		return err
+	}
+	functionCtx.registerHandler.Free(subjectRegIndex)
+
+	for i, clause := range clauses {
+		emitter.writeJumps(jumps[i])
+		emitter.ctxNode = clause.Children[len(clause.Children)-1]
+		err = emitter.block(functionCtx)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if i != len(clauses)-1 {
+			jumps[len(clauses)], err = emitter.reserveJump(jumps[len(clauses)])
+			if err != nil {
+				return err
+			}
 		}
 	}
+	emitter.writeJumps(jumps[len(clauses)])
+	emitter.ctxNode = backup
+	return nil
+}
 
-	//then we save again the previous registers in memory
-	err = emitter.takeRegistersFromMemory(emitter.offset - AmountOfRegistersToOperate - size)
-	if err != nil {
-		return nil, err
+//isDense returns true if the values of the cases of a switch are close enough to use a jump table
+func isDense(cases map[int]int) bool {
+	if len(cases) < MinCasesJumpTable {
+		return false
 	}
+	min, max := caseRange(cases)
+	span := max - min + 1
+	return span <= MaxJumpTable && span <= 2*len(cases)
+}
 
-	//and if it wasn't a void function, we save again the return value in a register
-	if size != 0 {
-		regIndex, ok := functionCtx.registerHandler.AllocSimple()
-		if !ok {
-			line := emitter.ctxNode.Value.Line
-			err := errors.New(errorhandler.TooManyRegisters(line))
-			return nil, err
+//caseRange returns the lowest and the highest value of the cases of a switch
+func caseRange(cases map[int]int) (int, int) {
+	min, max := 0xFF, 0
+	for value := range cases {
+		if value < min {
+			min = value
 		}
-		err := emitter.saveOpcode(I9XY1(RegisterStackAddress1, RegisterStackAddress2)) // I = address stack
-		if err != nil {
-			return nil, err
+		if value > max {
+			max = value
 		}
-		err = emitter.saveFX1ESafely(regIndex.lowBitsIndex, emitter.offset-size) //I = End of the register backup section/start of the return value backup section
-		if err != nil {
-			return nil, err
+	}
+	return min, max
+}
+
+//jumpChain compares the subject of a switch with the value of each case, and jumps to the block of the clause
+//that handles it. If none does, it jumps to the default
+func (emitter *Emitter) jumpChain(subjectRegIndex *ResultRegIndex, cases map[int]int, defaultClause int, jumps [][]uint16) error {
+	min, max := caseRange(cases)
+	var err error
+	for value := min; value <= max; value++ {
+		clause, ok := cases[value]
+		if !ok {
+			continue
 		}
-		err = emitter.saveOpcode(IFX65(byte(size - 1)))
+		err = emitter.saveOpcode(I4XKK(subjectRegIndex.lowBitsIndex, byte(value))) //if vx != value we skip the jump
 		if err != nil {
-			return nil, err
+			return err
 		}
-		//now the return value is again in 0
-		err = emitter.saveOpcode(I8XY0(regIndex.lowBitsIndex, 0)) //Vx = V0
+		jumps[clause], err = emitter.reserveJump(jumps[clause])
 		if err != nil {
-			return nil, err
+			return err
 		}
-		//	emitter.offset = backupOffsetStack
-		functionCtx.registerHandler.Free(aux)
-
-		return regIndex, nil
-
 	}
-	//	emitter.offset = backupOffsetStack
-	functionCtx.registerHandler.Free(aux)
-	return nil, nil
-
+	jumps[defaultClause], err = emitter.reserveJump(jumps[defaultClause])
+	return err
 }
 
-//backupRegistersInMemory stores the registers in the stack at position "offset" which receives as a parameter.
-//Returns an error if needed
-func (emitter *Emitter) backupRegistersInMemory(offset int) error {
-	err := emitter.saveOpcode(I9XY1(RegisterStackAddress1, RegisterStackAddress2)) // I = address stack
+//jumpTable writes a table with a jump for each value between the lowest and the highest case of a switch, and jumps
+//to the one of the value of the subject with BNNN. If the value is out of the table, it jumps to the default
+func (emitter *Emitter) jumpTable(subjectRegIndex *ResultRegIndex, cases map[int]int, defaultClause int, jumps [][]uint16) error {
+	min, max := caseRange(cases)
+	span := max - min + 1
+
+	err := emitter.saveOpcode(I8XY0(0, subjectRegIndex.lowBitsIndex)) //v0 = vx
 	if err != nil {
 		return err
 	}
-	err = emitter.saveFX1ESafely(0, offset) //I = I + offset
+	if min != 0 {
+		err = emitter.saveOpcode(I7XKK(0, byte(-min))) //v0 = vx - min, the index of the value in the table
+		if err != nil {
+			return err
+		}
+	}
+	//v0 is out of the table if v0 + (256 - span) > 255, in which case the carry is set to 1
+	err = emitter.saveOpcode(I6XKK(1, byte(256-span)))
 	if err != nil {
 		return err
 	}
-	err = emitter.saveOpcode(IFX55(AmountOfRegistersToOperate - 1))
+	err = emitter.saveOpcode(I8XY4(1, 0))
 	if err != nil {
 		return err
 	}
-
-	return nil
-}
-
-//takeRegistersFromMemory reads the registers from the stack at position "offset" which it receives as a parameter.
-//Returns an error if needed
-func (emitter *Emitter) takeRegistersFromMemory(offset int) error {
-	err := emitter.saveOpcode(I9XY1(RegisterStackAddress1, RegisterStackAddress2)) // I = address stack
+	err = emitter.saveOpcode(I3XKK(Carry, False)) //if carry = false we skip the jump to the default
 	if err != nil {
 		return err
 	}
-	err = emitter.saveFX1ESafely(0, offset) //I = I + offset
+	jumps[defaultClause], err = emitter.reserveJump(jumps[defaultClause])
 	if err != nil {
 		return err
 	}
-	err = emitter.saveOpcode(IFX65(AmountOfRegistersToOperate - 1))
+	//each jump of the table takes two bytes, so we jump to the address of the table + v0 * 2
+	err = emitter.saveOpcode(I8XY4(0, 0))
 	if err != nil {
 		return err
 	}
-	return nil
-}
-
-//saveParamsInRegisters saves the params of a function call in registers from v2. Returns an error if needed
-func (emitter *Emitter) saveParamsInRegisters(functionCtx *FunctionCtx, ident string) error {
-	const PARAMS = 1
-	backupNode := emitter.ctxNode
-	if len(emitter.ctxNode.Children) > 1 { //we ask if it has any param
-		function := emitter.scope.Symbols[ident]
-		params := function.DataType.(symboltable.Function).Args
-		emitter.ctxNode = emitter.ctxNode.Children[PARAMS]
-		i := 2 //we store the params in registers from v2
-
-		backupRegisterHandler := functionCtx.registerHandler
-		functionCtx.registerHandler = NewRegisterHandler() //we free all the registers
-
-		for emitter.ctxNode.Value.Type == token.COMMA {
-			backupComma := emitter.ctxNode
-			emitter.ctxNode = emitter.ctxNode.Children[0]
-			err := emitter.saveParamInRegisters(functionCtx, params, i)
-			if err != nil {
-				return err
-			}
-			i += 1
-			emitter.ctxNode = backupComma
-			emitter.ctxNode = emitter.ctxNode.Children[1]
+	err = emitter.saveOpcode(IBNNN(emitter.currentAddress + 2))
+	if err != nil {
+		return err
+	}
+	for value := min; value <= max; value++ {
+		clause, ok := cases[value]
+		if !ok {
+			clause = defaultClause
 		}
-		err := emitter.saveParamInRegisters(functionCtx, params, i)
+		jumps[clause], err = emitter.reserveJump(jumps[clause])
 		if err != nil {
 			return err
 		}
-
-		functionCtx.registerHandler = backupRegisterHandler
-
 	}
-	emitter.ctxNode = backupNode
 	return nil
 }
 
-//saveParamsInRegisters saves the param of a function call in the register v_i-2 (and v_i-1 if needed).
-//Returns an error if needed
-func (emitter *Emitter) saveParamInRegisters(functionCtx *FunctionCtx, params []interface{}, i int) error {
-	//because we free the rest of registers, it allocates the params in order from v2
-	paramRegIndex, ok := functionCtx.registerHandler.Alloc(params[i-2])
-	if !ok {
-		line := emitter.ctxNode.Value.Line
-		err := errors.New(errorhandler.TooManyRegisters(line))
-		return err
+//reserveJump saves the current address in jumps and moves forward, so the jump can be written later by writeJumps
+func (emitter *Emitter) reserveJump(jumps []uint16) ([]uint16, error) {
+	jumps = append(jumps, emitter.currentAddress)
+	err := emitter.moveCurrentAddress()
+	if err != nil {
+		return nil, err
 	}
+	return jumps, emitter.moveCurrentAddress()
+}
 
-	//we save in registers the value of the parameter being analyzed
-	resultRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
-	if err != nil {
-		return err
+//writeJumps writes in each of the addresses reserved in jumps a jump to the current address
+func (emitter *Emitter) writeJumps(jumps []uint16) {
+	jump := I1NNN(emitter.currentAddress)
+	for _, address := range jumps {
+		emitter.machineCode[address] = jump[0]
+		emitter.machineCode[address+1] = jump[1]
 	}
+}
 
-	//and we save the result in the register reserved for that param
-	err = emitter.saveOpcode(I8XY0(paramRegIndex.lowBitsIndex, resultRegIndex.lowBitsIndex))
+//label translates a labeled loop to opcodes and write it in emitter.machineCode
+func (emitter *Emitter) label(functionCtx *FunctionCtx) error {
+	const LABEL = 0
+	const LOOP = 1
+	functionCtx.label = emitter.ctxNode.Children[LABEL].Value.Literal
+	emitter.ctxNode = emitter.ctxNode.Children[LOOP]
+	return emitter.translateStatement[emitter.ctxNode.Value.Type](functionCtx)
+}
 
+//_break translates a break statement to a jump to the end of its loop and write it in emitter.machineCode
+func (emitter *Emitter) _break(functionCtx *FunctionCtx) error {
+	loop := functionCtx.findLoop(emitter.loopControlLabel())
+	//because we don't know yet where the loop ends, we save the current address to write the jump later
+	loop.breakJump = append(loop.breakJump, emitter.currentAddress)
+	err := emitter.moveCurrentAddress()
 	if err != nil {
 		return err
 	}
-	if paramRegIndex.isPointer {
-		err = emitter.saveOpcode(I8XY0(paramRegIndex.highBitsIndex, resultRegIndex.highBitsIndex))
+	return emitter.moveCurrentAddress()
+}
+
+//_continue translates a continue statement to a jump to the condition of a while, or to the step of a for,
+//and write it in emitter.machineCode
+func (emitter *Emitter) _continue(functionCtx *FunctionCtx) error {
+	loop := functionCtx.findLoop(emitter.loopControlLabel())
+	if loop.isFor {
+		//because the step is written after the block, we save the current address to write the jump later
+		loop.continueJump = append(loop.continueJump, emitter.currentAddress)
+		err := emitter.moveCurrentAddress()
 		if err != nil {
 			return err
 		}
-
+		return emitter.moveCurrentAddress()
 	}
-	functionCtx.registerHandler.Free(resultRegIndex)
-	return nil
+	return emitter.saveOpcode(I1NNN(loop.start))
 }
 
-//_byte save a byte in a registers. Return the register index in which the byte was stored and an error if needed
-func (emitter *Emitter) _byte(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	regIndex, ok := functionCtx.registerHandler.AllocSimple()
-	if !ok {
-		line := emitter.ctxNode.Value.Line
-		err := errors.New(errorhandler.TooManyRegisters(line))
-		return regIndex, err
+//loopControlLabel returns the label of a break or a continue, or an empty string if it has none
+func (emitter *Emitter) loopControlLabel() string {
+	if len(emitter.ctxNode.Children) == 0 {
+		return ""
 	}
+	return emitter.ctxNode.Children[0].Value.Literal
+}
 
-	kk, _ := strconv.Atoi(emitter.ctxNode.Value.Literal)
-	err := emitter.saveOpcode(I6XKK(regIndex.lowBitsIndex, byte(kk))) // Vx = Byte
-	if err != nil {
-		return regIndex, err
+//block translates a block  to opcodes and write it in emitter.machineCode, it also handle the scope
+func (emitter *Emitter) block(functionCtx *FunctionCtx) error {
+	lastIndexSubScopeBackup := emitter.lastIndexSubScope
+	scopeBackup := emitter.scope
+	addressesBackup := functionCtx.stack
+	emitter.scope = emitter.scope.SubScopes[emitter.lastIndexSubScope]
+	functionCtx.stack = functionCtx.stack.SubReferences[emitter.lastIndexSubScope]
+	emitter.lastIndexSubScope = 0
+
+	block := emitter.ctxNode
+
+	//let statements were already declared at the beginning of the function, so here we only write their initialization
+	for _, child := range block.Children {
+		emitter.ctxNode = child
+		err := emitter.translateStatement[emitter.ctxNode.Value.Type](functionCtx)
+		if err != nil {
+			return err
+		}
 	}
 
-	return regIndex, nil
+	emitter.lastIndexSubScope = lastIndexSubScopeBackup + 1
+	emitter.scope = scopeBackup
+	functionCtx.stack = addressesBackup
+	return nil
 }
 
-//boolean save a bool in a registers. Return the register index in which the bool was stored and an error if needed
-func (emitter *Emitter) boolean(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	regIndex, ok := functionCtx.registerHandler.AllocSimple()
-	if !ok {
-		line := emitter.ctxNode.Value.Line
-		err := errors.New(errorhandler.TooManyRegisters(line))
-		return nil, err
-	}
+//bracket analyze save a dereference in registers,
+//returns the index of registers in which the result of the operation was stored and an error if needed
+func (emitter *Emitter) bracket(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	return emitter.saveDereferenceInRegisters(functionCtx)
+}
 
-	var kk byte
-	if emitter.ctxNode.Value.Literal == token.TRUE {
-		kk = True
-	} else {
-		kk = False
+//parenthesis analyze the context of a parenthesis and delegate the operation,
+//returns the index of registers in which the result of the operation was stored and an error if needed
+func (emitter *Emitter) parenthesis(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	if emitter.ctxNode.Children[0].Value.Type == token.IDENT && !emitter.isAType(emitter.ctxNode.Children[0]) {
+		return emitter.call(functionCtx)
 	}
-	err := emitter.saveOpcode(I6XKK(regIndex.lowBitsIndex, kk))
-	if err != nil {
-		return nil, err
+	if len(emitter.ctxNode.Children) == 2 {
+		return emitter.conversion(functionCtx)
 	}
-	return regIndex, nil
+	emitter.ctxNode = emitter.ctxNode.Children[0] //skip node
+	return emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
 }
 
-//ltgt translates < and > to opcodes and write it in emitter.machineCode,
-//returns the index of register in which the result is stored and an error
-func (emitter *Emitter) ltgt(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	leftOperandRegIndex, rightOperandRegIndex, err := emitter.solveOperands(functionCtx)
+//conversion translates a conversion, which doesn't need any opcode unless the representation of the value changes:
+//a byte converted to a word or a pointer is widened, an int8 is widened keeping its sign, a word or a pointer converted
+//to a byte keeps only its low bits, and a value converted to a bool is 1 unless it is 0.
+//Returns the indexes of the registers in which the value is stored and an error if needed
+func (emitter *Emitter) conversion(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	const DATATYPE = 0
+	const VALUE = 1
+	datatype := emitter.ctxNode.Children[DATATYPE].Value.Type
+	value := emitter.ctxNode.Children[VALUE]
+	emitter.ctxNode = value
+	regIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
 	if err != nil {
 		return nil, err
 	}
+	switch datatype {
+	case token.TYPEWORD, token.ASTERISK:
+		if !regIndex.isPointer && emitter.isSigned(value) {
+			return emitter.signExtend(functionCtx, regIndex)
+		}
+		if !regIndex.isPointer {
+			return emitter.widen(functionCtx, regIndex)
+		}
+	case token.TYPEBYTE, token.TYPEINT8:
+		if regIndex.isPointer {
+			functionCtx.registerHandler.Free(&ResultRegIndex{lowBitsIndex: regIndex.highBitsIndex})
+			return &ResultRegIndex{lowBitsIndex: regIndex.lowBitsIndex, isPointer: false}, nil
+		}
+	case token.TYPEBOOL:
+		return emitter.toBool(functionCtx, value, regIndex)
+	}
+	return regIndex, nil
+}
 
-	resultRegIndex, ok := functionCtx.registerHandler.AllocSimple() //the result is a bool
+//toBool converts the value led by node, saved in regIndex, to a bool: it is false if all of its bits are 0 and true if not.
+//A bool is already saved as 1 or 0, so it doesn't need any opcode.
+//Returns the index of the register in which the bool is stored and an error if needed
+func (emitter *Emitter) toBool(functionCtx *FunctionCtx, node *ast.Node, regIndex *ResultRegIndex) (*ResultRegIndex, error) {
+	datatype, ok := emitter.dataTypeOf(node)
+	if ok && symboltable.Compare(datatype, symboltable.NewBool()) {
+		return regIndex, nil
+	}
+	if regIndex.isPointer {
+		//the low bits are 0 only if both of them were 0
+		err := emitter.saveOpcode(I8XY1(regIndex.lowBitsIndex, regIndex.highBitsIndex))
+		if err != nil {
+			return nil, err
+		}
+		functionCtx.registerHandler.Free(&ResultRegIndex{lowBitsIndex: regIndex.highBitsIndex})
+		regIndex = &ResultRegIndex{lowBitsIndex: regIndex.lowBitsIndex, isPointer: false}
+	}
+	err := emitter.saveOpcode(I3XKK(regIndex.lowBitsIndex, 0)) //if vx = 0 we skip the next opcode
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.saveOpcode(I6XKK(regIndex.lowBitsIndex, True))
+	if err != nil {
+		return nil, err
+	}
+	return regIndex, nil
+}
+
+//voidCall translates a void call to opcodes and write it in emitter.machineCode
+func (emitter *Emitter) voidCall(functionCtx *FunctionCtx) error {
+	_, err := emitter.call(functionCtx)
+	return err
+}
+
+//call translates a function call to opcodes and write it in emitter.machineCode,
+//returns the indexes of registers in which the return value is stored and an error if needed
+func (emitter *Emitter) call(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	const IDENT = 0
+	aux, ok := functionCtx.registerHandler.AllocSimple()
 	if !ok {
 		line := emitter.ctxNode.Value.Line
 		err := errors.New(errorhandler.TooManyRegisters(line))
 		return nil, err
+
 	}
-	// if our operands are simples we just need to check if vx is lesser/greater than vy.
-	//we first save this information in carry (vf) and then we set vz = carry
-	//( where vz is  the register in which we store the result)
-	if !leftOperandRegIndex.isPointer {
+	ident := emitter.ctxNode.Children[IDENT].Value.Literal
 
-		switch emitter.ctxNode.Value.Type {
-		case token.GT:
-			err = emitter.saveOpcode(I8XY5(leftOperandRegIndex.lowBitsIndex, rightOperandRegIndex.lowBitsIndex))
-			if err != nil {
-				return nil, err
-			}
-		case token.LT:
-			err = emitter.saveOpcode(I8XY7(leftOperandRegIndex.lowBitsIndex, rightOperandRegIndex.lowBitsIndex))
-			if err != nil {
-				return nil, err
-			}
-		default:
-			return nil, errors.New(errorhandler.UnexpectedCompilerError())
+	//each level of nested calls has its own backup section in the frame, after the params and variables
+	backupOffset := functionCtx.localsSize + functionCtx.callDepth*SizeCallBackup
+	returnValueOffset := backupOffset + AmountOfRegistersToOperate
+	functionCtx.callDepth++
+	if functionCtx.callDepth > functionCtx.maxCallDepth {
+		functionCtx.maxCallDepth = functionCtx.callDepth
+	}
+
+	//we first backup all registers of the current function in the stack
+	err := emitter.backupRegistersInMemory(backupOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	//then we save the params of the function call in registers
+	err = emitter.saveParamsInRegisters(functionCtx, ident)
+	if err != nil {
+		return nil, err
+	}
+
+	//the frame of the function we call starts after the frame of the current function, so we move the stack pointer forward
+	err = emitter.reserveMoveStackPointer(functionCtx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	//we call the function, because it could be declared after the current one, we write the call once we know its address
+	emitter.callPatches = append(emitter.callPatches, &CallPatch{address: emitter.currentAddress, function: ident})
+	err = emitter.moveCurrentAddress()
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.moveCurrentAddress()
+	if err != nil {
+		return nil, err
+	}
+	//if it was not a void function, now the return value is in v0, or in v0 and v1 if it has two bytes.
+
+	//we move back the stack pointer to the frame of the current function
+	err = emitter.reserveMoveStackPointer(functionCtx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	//if the function we call was not a void function, then we save in memory a backup of the return value,
+	//because we will need the registers v0 and v1
+	returnDataType := emitter.scope.Symbols[ident].DataType.(symboltable.Function).Return
+	size := symboltable.GetSize(returnDataType)
+	if size != 0 {
+		err := emitter.saveOpcode(I9XY1(RegisterStackAddress1, RegisterStackAddress2)) // I = stack address
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		//if we are comparing pointers we first compare vx0 with vy0
-		//we backup vx0 in v0 in case we need the original value
-		err = emitter.saveOpcode(I8XY0(0, leftOperandRegIndex.highBitsIndex)) //v0 = vx0
+		err = emitter.saveFX1ESafely(aux.lowBitsIndex, returnValueOffset) //I = I + offset
+		if err != nil {
+			return nil, err
+		}
+		err = emitter.saveOpcode(IFX55(byte(size - 1)))
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		switch emitter.ctxNode.Value.Type {
-		case token.GT:
-			err = emitter.saveOpcode(I8XY5(leftOperandRegIndex.highBitsIndex, rightOperandRegIndex.highBitsIndex))
-			if err != nil {
-				return nil, err
-			}
-		case token.LT:
-			err = emitter.saveOpcode(I8XY7(leftOperandRegIndex.highBitsIndex, rightOperandRegIndex.highBitsIndex))
-			if err != nil {
-				return nil, err
-			}
-		default:
-			return nil, errors.New(errorhandler.UnexpectedCompilerError())
+	//then we save again the previous registers in memory
+	err = emitter.takeRegistersFromMemory(backupOffset)
+	if err != nil {
+		return nil, err
+	}
+	functionCtx.callDepth--
 
-		}
-		err = emitter.saveOpcode(I3XKK(Carry, False)) //if carry = false we keep analyzing
-		if err != nil {
+	//and if it wasn't a void function, we save again the return value in a register
+	if size != 0 {
+		regIndex, ok := functionCtx.registerHandler.Alloc(returnDataType)
+		if !ok {
+			line := emitter.ctxNode.Value.Line
+			err := errors.New(errorhandler.TooManyRegisters(line))
 			return nil, err
 		}
-		err = emitter.saveOpcode(I1NNN(emitter.currentAddress + 5)) //if carry = True we jump to the end
+		err := emitter.saveOpcode(I9XY1(RegisterStackAddress1, RegisterStackAddress2)) // I = address stack
 		if err != nil {
 			return nil, err
 		}
-		//if carry = false we ask if v0 == vy0 with a xor (v0 saves the original value of vx0)
-		err = emitter.saveOpcode(I8XY3(0, rightOperandRegIndex.highBitsIndex))
+		err = emitter.saveFX1ESafely(regIndex.lowBitsIndex, returnValueOffset) //I = start of the return value backup section
 		if err != nil {
 			return nil, err
 		}
-		//if v0 == vy0, now v0 = 0
-		err = emitter.saveOpcode(I4XKK(leftOperandRegIndex.highBitsIndex, 0)) //if vx0 != vy0 we skip the next opcode
+		err = emitter.saveOpcode(IFX65(byte(size - 1)))
 		if err != nil {
 			return nil, err
 		}
-
-		//if vx0 == vy0 we need to analyze vx1 and vy1
-		switch emitter.ctxNode.Value.Type {
-		case token.GT:
-			err = emitter.saveOpcode(I8XY5(leftOperandRegIndex.lowBitsIndex, rightOperandRegIndex.lowBitsIndex))
-			if err != nil {
-				return nil, err
-			}
-		case token.LT:
-			err = emitter.saveOpcode(I8XY7(leftOperandRegIndex.lowBitsIndex, rightOperandRegIndex.lowBitsIndex))
+		//now the return value is again in v0 (and v1)
+		if regIndex.isPointer {
+			err = emitter.saveOpcode(I8XY0(regIndex.highBitsIndex, 0)) //Vx = V0
 			if err != nil {
 				return nil, err
 			}
-		default:
-			return nil, errors.New(errorhandler.UnexpectedCompilerError())
+			err = emitter.saveOpcode(I8XY0(regIndex.lowBitsIndex, 1)) //Vy = V1
+		} else {
+			err = emitter.saveOpcode(I8XY0(regIndex.lowBitsIndex, 0)) //Vx = V0
+		}
+		if err != nil {
+			return nil, err
+		}
+		functionCtx.registerHandler.Free(aux)
+
+		return regIndex, nil
+
+	}
+	functionCtx.registerHandler.Free(aux)
+	return nil, nil
+
+}
 
+//reserveMoveStackPointer leaves space for the opcodes that move the stack pointer by the size of the frame of the function,
+//because we don't know this size yet, we save the current address in functionCtx to write the opcodes later.
+//Returns an error if needed
+func (emitter *Emitter) reserveMoveStackPointer(functionCtx *FunctionCtx, forward bool) error {
+	patch := &StackPointerPatch{address: emitter.currentAddress, forward: forward}
+	functionCtx.stackPointerPatches = append(functionCtx.stackPointerPatches, patch)
+	for i := 0; i < SizeMoveStackPointer; i++ {
+		err := emitter.moveCurrentAddress()
+		if err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
+//writeMoveStackPointer writes the opcodes that move the stack pointer by frameSize in the address of the patch.
+//To move it back we add the two's complement of frameSize, so both directions only depend on the carry of 8XY4
+func (emitter *Emitter) writeMoveStackPointer(patch *StackPointerPatch, frameSize int) {
+	delta := uint16(frameSize)
+	aux := byte(0) //when we move forward the params are saved from v2, so v0 is free
+	if !patch.forward {
+		delta = -delta
+		aux = 2 //when we move back v0 and v1 store the return value, so we use v2
+	}
+	opcodes := []Opcode{
+		I6XKK(aux, byte(delta)),                      //aux = last 8 bits of delta
+		I8XY4(RegisterStackAddress2, aux),            //vE = vE + aux
+		I4XKK(Carry, True),                           //if carry = false we skip the next opcode
+		I7XKK(RegisterStackAddress1, 1),              //vD = vD + 1
+		I7XKK(RegisterStackAddress1, byte(delta>>8)), //vD = vD + first 8 bits of delta
+	}
+	address := patch.address
+	for _, opcode := range opcodes {
+		emitter.machineCode[address] = opcode[0]
+		emitter.machineCode[address+1] = opcode[1]
+		address += 2
 	}
-	//we save the result in vz
-	err = emitter.saveOpcode(I8XY0(resultRegIndex.lowBitsIndex, Carry))
+}
+
+//backupRegistersInMemory stores the registers in the frame at position "offset" which receives as a parameter.
+//Returns an error if needed
+func (emitter *Emitter) backupRegistersInMemory(offset int) error {
+	err := emitter.saveOpcode(I9XY1(RegisterStackAddress1, RegisterStackAddress2)) // I = address stack
 	if err != nil {
-		return nil, err
+		return err
+	}
+	err = emitter.saveFX1ESafely(0, offset) //I = I + offset
+	if err != nil {
+		return err
+	}
+	err = emitter.saveOpcode(IFX55(AmountOfRegistersToOperate - 1))
+	if err != nil {
+		return err
 	}
-	functionCtx.registerHandler.Free(leftOperandRegIndex)
-	functionCtx.registerHandler.Free(rightOperandRegIndex)
-	return resultRegIndex, nil
 
+	return nil
 }
 
-//ltgteq translates <= and >= to opcodes and write it in emitter.machineCode,
-//returns the index of register in which the result is stored and an error
-func (emitter *Emitter) ltgteq(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	leftOperandRegIndex, rightOperandRegIndex, err := emitter.solveOperands(functionCtx)
+//takeRegistersFromMemory reads the registers from the frame at position "offset" which it receives as a parameter.
+//Returns an error if needed
+func (emitter *Emitter) takeRegistersFromMemory(offset int) error {
+	err := emitter.saveOpcode(I9XY1(RegisterStackAddress1, RegisterStackAddress2)) // I = address stack
 	if err != nil {
-		return nil, err
+		return err
+	}
+	err = emitter.saveFX1ESafely(0, offset) //I = I + offset
+	if err != nil {
+		return err
+	}
+	err = emitter.saveOpcode(IFX65(AmountOfRegistersToOperate - 1))
+	if err != nil {
+		return err
 	}
+	return nil
+}
 
-	resultRegIndex, ok := functionCtx.registerHandler.AllocSimple() //the result is a bool
+//spillRegisters saves the registers from v0 to the stack pointer in the spill area, because a block copy or comparison
+//uses all of them as a staging buffer. The spill area is written between the opcodes the first time it is needed.
+//Returns an error if needed
+func (emitter *Emitter) spillRegisters() error {
+	if emitter.spillArea == 0 {
+		address, err := emitter.saveData(make([]byte, SizeSpill))
+		if err != nil {
+			return err
+		}
+		emitter.spillArea = address
+	}
+	err := emitter.saveOpcode(IANNN(emitter.spillArea))
+	if err != nil {
+		return err
+	}
+	return emitter.saveOpcode(IFX55(SizeSpill - 1))
+}
+
+//restoreRegisters reads the registers from v0 to the stack pointer from the spill area. Returns an error if needed
+func (emitter *Emitter) restoreRegisters() error {
+	err := emitter.saveOpcode(IANNN(emitter.spillArea))
+	if err != nil {
+		return err
+	}
+	return emitter.saveOpcode(IFX65(SizeSpill - 1))
+}
+
+//reserveBlockAddresses computes the address of each block of the array led by the ctx node, and saves it as an opcode
+//I = address in a place of the memory that is not known yet, because once the registers are used as a staging buffer
+//there are no registers left to compute it. Returns the addresses of the opcodes that save each opcode I = address,
+//which are written by writeBlockAddress, and an error if needed
+func (emitter *Emitter) reserveBlockAddresses(functionCtx *FunctionCtx, blocks int, sizeBlock int) ([]uint16, error) {
+	_, err := emitter.saveVariableAddressInI(functionCtx)
+	if err != nil {
+		return nil, err
+	}
+	addressRegIndex, ok := functionCtx.registerHandler.AllocPointer()
 	if !ok {
 		line := emitter.ctxNode.Value.Line
-		err := errors.New(errorhandler.TooManyRegisters(line))
+		return nil, errors.New(errorhandler.TooManyRegisters(line))
+	}
+	aux, ok := functionCtx.registerHandler.AllocSimple()
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		return nil, errors.New(errorhandler.TooManyRegisters(line))
+	}
+	err = emitter.saveOpcode(I9XY2(addressRegIndex.highBitsIndex, addressRegIndex.lowBitsIndex))
+	if err != nil {
 		return nil, err
 	}
-
-	if !leftOperandRegIndex.isPointer {
-		err = emitter.saveOpcode(I6XKK(Carry, True)) //vf = 1
+	patches := make([]uint16, blocks)
+	for i := range patches {
+		err = emitter.saveOpcode(I9XY1(addressRegIndex.highBitsIndex, addressRegIndex.lowBitsIndex)) //I = address of the array
 		if err != nil {
 			return nil, err
 		}
-		//we backup vx in v0
-		err = emitter.saveOpcode(I8XY0(0, leftOperandRegIndex.lowBitsIndex)) //v0=vx
+		err = emitter.saveFX1ESafely(aux.lowBitsIndex, i*sizeBlock) //I = address of the block
 		if err != nil {
 			return nil, err
 		}
-
-		err = emitter.saveOpcode(I8XY3(0,
-			rightOperandRegIndex.lowBitsIndex)) //we ask v0 == vy with a xor
+		//v0 and v1 = the opcode I = address of the block
+		err = emitter.saveOpcode(I9XY2(0, 1))
 		if err != nil {
 			return nil, err
 		}
-
-		err = emitter.saveOpcode(I3XKK(0, 0)) //if v0 = 0 then vx was equal to vy and we skip the next opcode
+		err = emitter.saveOpcode(I6XKK(aux.lowBitsIndex, 0xA0))
 		if err != nil {
 			return nil, err
 		}
-		switch emitter.ctxNode.Value.Type {
-		case token.GTEQ:
-			err = emitter.saveOpcode(I8XY5(leftOperandRegIndex.lowBitsIndex,
-				rightOperandRegIndex.lowBitsIndex)) //if vx wasn't equal to vy, we ask if vx > vy and store the result in vf
-			if err != nil {
-				return nil, err
-			}
-		case token.LTEQ:
-			err = emitter.saveOpcode(I8XY7(leftOperandRegIndex.lowBitsIndex,
-				rightOperandRegIndex.lowBitsIndex)) //if vx wasn't equal to vy, we ask if vx < vy and store the result in vf
-			if err != nil {
-				return nil, err
-			}
-		default:
-			return nil, errors.New(errorhandler.UnexpectedCompilerError())
-
+		err = emitter.saveOpcode(I8XY1(0, aux.lowBitsIndex))
+		if err != nil {
+			return nil, err
 		}
-		//then we save the result in a new register
-		err = emitter.saveOpcode(I8XY0(resultRegIndex.lowBitsIndex, Carry))
+		//we save the opcode once we know where, so we leave space for I = that place
+		patches[i] = emitter.currentAddress
+		err = emitter.moveCurrentAddress()
 		if err != nil {
 			return nil, err
 		}
-
-	} else {
-		//we first save in v0 the high bits of vx and in v1 the low bits
-		err = emitter.saveOpcode(I8XY0(0, leftOperandRegIndex.highBitsIndex))
+		err = emitter.moveCurrentAddress()
 		if err != nil {
 			return nil, err
 		}
-		err = emitter.saveOpcode(I8XY0(1, leftOperandRegIndex.lowBitsIndex))
+		err = emitter.saveOpcode(IFX55(1))
 		if err != nil {
 			return nil, err
 		}
+	}
+	functionCtx.registerHandler.Free(aux)
+	functionCtx.registerHandler.Free(addressRegIndex)
+	return patches, nil
+}
 
-		//we ask if v1 (which saves the same value than vx1) is greater/lesser than vy and store the result in vf (carry)
-		switch emitter.ctxNode.Value.Type {
-		case token.GTEQ:
-			err = emitter.saveOpcode(I8XY5(1, rightOperandRegIndex.highBitsIndex))
-			if err != nil {
-				return nil, err
-			}
-		case token.LTEQ:
-			err = emitter.saveOpcode(I8XY7(1, rightOperandRegIndex.highBitsIndex))
+//writeBlockAddress writes in the address of the patch an opcode I = current address, so the address of a block
+//computed by reserveBlockAddresses is saved in the current address, where it is executed as the opcode I = address of the block.
+//Returns an error if needed
+func (emitter *Emitter) writeBlockAddress(patch uint16) error {
+	opcode := IANNN(emitter.currentAddress)
+	emitter.machineCode[patch] = opcode[0]
+	emitter.machineCode[patch+1] = opcode[1]
+	return emitter.saveOpcode(IANNN(0)) //it is overwritten before being executed
+}
+
+//saveParamsInRegisters saves the params of a function call in registers from v2. Returns an error if needed
+func (emitter *Emitter) saveParamsInRegisters(functionCtx *FunctionCtx, ident string) error {
+	const PARAMS = 1
+	backupNode := emitter.ctxNode
+	if len(emitter.ctxNode.Children) > 1 { //we ask if it has any param
+		function := emitter.scope.Symbols[ident]
+		params := function.DataType.(symboltable.Function).Args
+		emitter.ctxNode = emitter.ctxNode.Children[PARAMS]
+		i := 2 //we store the params in registers from v2
+
+		backupRegisterHandler := functionCtx.registerHandler
+		functionCtx.registerHandler = NewRegisterHandler() //we free all the registers
+
+		for emitter.ctxNode.Value.Type == token.COMMA {
+			backupComma := emitter.ctxNode
+			emitter.ctxNode = emitter.ctxNode.Children[0]
+			err := emitter.saveParamInRegisters(functionCtx, params, i)
 			if err != nil {
-				return nil, err
+				return err
 			}
-		default:
-			return nil, errors.New(errorhandler.UnexpectedCompilerError())
-
+			i += 1
+			emitter.ctxNode = backupComma
+			emitter.ctxNode = emitter.ctxNode.Children[1]
 		}
-
-		err = emitter.saveOpcode(I3XKK(Carry, False)) //if carry = false we skip  the next opcode
+		err := emitter.saveParamInRegisters(functionCtx, params, i)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		err = emitter.saveOpcode(I1NNN(emitter.currentAddress + 9*2)) //if carry = true we skip 9 opcodes because we know the result is true
+
+		functionCtx.registerHandler = backupRegisterHandler
+
+	}
+	emitter.ctxNode = backupNode
+	return nil
+}
+
+//saveParamsInRegisters saves the param of a function call in the register v_i-2 (and v_i-1 if needed).
+//Returns an error if needed
+func (emitter *Emitter) saveParamInRegisters(functionCtx *FunctionCtx, params []interface{}, i int) error {
+	//sprite literals are already translated to the address of their first row
+	datatype, ok := emitter.dataTypeOf(emitter.ctxNode)
+	if ok && symboltable.IsAnArray(datatype) && emitter.ctxNode.Value.Type != token.SPRITE {
+		return emitter.saveArrayParamInRegisters(functionCtx)
+	}
+	//because we free the rest of registers, it allocates the params in order from v2
+	paramRegIndex, ok := functionCtx.registerHandler.Alloc(params[i-2])
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		err := errors.New(errorhandler.TooManyRegisters(line))
+		return err
+	}
+
+	//we save in registers the value of the parameter being analyzed
+	resultRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
+	if err != nil {
+		return err
+	}
+
+	//and we save the result in the register reserved for that param
+	err = emitter.saveOpcode(I8XY0(paramRegIndex.lowBitsIndex, resultRegIndex.lowBitsIndex))
+
+	if err != nil {
+		return err
+	}
+	if paramRegIndex.isPointer {
+		if resultRegIndex.isPointer {
+			err = emitter.saveOpcode(I8XY0(paramRegIndex.highBitsIndex, resultRegIndex.highBitsIndex))
+		} else {
+			//a byte passed as a word is widened
+			err = emitter.saveOpcode(I6XKK(paramRegIndex.highBitsIndex, 0))
+		}
+		if err != nil {
+			return err
+		}
+
+	}
+	functionCtx.registerHandler.Free(resultRegIndex)
+	return nil
+}
+
+//saveArrayParamInRegisters saves in the next two registers the address of the first element of the array passed as a param,
+//whether the param is an array or a pointer. Returns an error if needed
+func (emitter *Emitter) saveArrayParamInRegisters(functionCtx *FunctionCtx) error {
+	paramRegIndex, ok := functionCtx.registerHandler.AllocPointer()
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		return errors.New(errorhandler.TooManyRegisters(line))
+	}
+	_, err := emitter.saveVariableAddressInI(functionCtx)
+	if err != nil {
+		return err
+	}
+	return emitter.saveOpcode(I9XY2(paramRegIndex.highBitsIndex, paramRegIndex.lowBitsIndex))
+}
+
+//_byte save a byte in a registers. Return the register index in which the byte was stored and an error if needed
+func (emitter *Emitter) _byte(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	regIndex, ok := functionCtx.registerHandler.AllocSimple()
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		err := errors.New(errorhandler.TooManyRegisters(line))
+		return regIndex, err
+	}
+
+	kk, _ := strconv.Atoi(emitter.ctxNode.Value.Literal)
+	err := emitter.saveOpcode(I6XKK(regIndex.lowBitsIndex, byte(kk))) // Vx = Byte
+	if err != nil {
+		return regIndex, err
+	}
+
+	return regIndex, nil
+}
+
+//boolean save a bool in a registers. Return the register index in which the bool was stored and an error if needed
+func (emitter *Emitter) boolean(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	regIndex, ok := functionCtx.registerHandler.AllocSimple()
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		err := errors.New(errorhandler.TooManyRegisters(line))
+		return nil, err
+	}
+
+	var kk byte
+	if emitter.ctxNode.Value.Literal == token.TRUE {
+		kk = True
+	} else {
+		kk = False
+	}
+	err := emitter.saveOpcode(I6XKK(regIndex.lowBitsIndex, kk))
+	if err != nil {
+		return nil, err
+	}
+	return regIndex, nil
+}
+
+//ltgt translates < and > to opcodes and write it in emitter.machineCode,
+//returns the index of register in which the result is stored and an error
+func (emitter *Emitter) ltgt(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	leftOperandRegIndex, rightOperandRegIndex, err := emitter.solveOperands(functionCtx)
+	if err != nil {
+		return nil, err
+	}
+	leftOperandRegIndex, rightOperandRegIndex, err = emitter.balanceOperands(functionCtx, leftOperandRegIndex, rightOperandRegIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	resultRegIndex, ok := functionCtx.registerHandler.AllocSimple() //the result is a bool
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		err := errors.New(errorhandler.TooManyRegisters(line))
+		return nil, err
+	}
+	err = emitter.flipSignBits(leftOperandRegIndex, rightOperandRegIndex)
+	if err != nil {
+		return nil, err
+	}
+	//if we are comparing words or pointers, the low bits are only compared if the high bits are equal
+	var jumps []uint16
+	if leftOperandRegIndex.isPointer {
+		jumps, err = emitter.compareHighBits(leftOperandRegIndex, rightOperandRegIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// if our operands are simples (or the low bits of words) we just need to check if vx is lesser/greater than vy.
+	//we first save this information in carry (vf) and then we set vz = carry
+	//( where vz is  the register in which we store the result)
+	switch emitter.ctxNode.Value.Type {
+	case token.GT:
+		err = emitter.saveOpcode(I8XY5(leftOperandRegIndex.lowBitsIndex, rightOperandRegIndex.lowBitsIndex))
+		if err != nil {
+			return nil, err
+		}
+	case token.LT:
+		err = emitter.saveOpcode(I8XY7(leftOperandRegIndex.lowBitsIndex, rightOperandRegIndex.lowBitsIndex))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New(errorhandler.UnexpectedCompilerError())
+	}
+	emitter.writeJumps(jumps)
+
+	//we save the result in vz
+	err = emitter.saveOpcode(I8XY0(resultRegIndex.lowBitsIndex, Carry))
+	if err != nil {
+		return nil, err
+	}
+	functionCtx.registerHandler.Free(leftOperandRegIndex)
+	functionCtx.registerHandler.Free(rightOperandRegIndex)
+	return resultRegIndex, nil
+
+}
+
+//flipSignBits flips the highest bit of two int8 operands of a comparison, so the comparison of the registers,
+//which is unsigned, gives the result of a signed comparison: -128 becomes 0 and 127 becomes 255.
+//It doesn't write any opcode if none of the operands is signed. Returns an error if needed
+func (emitter *Emitter) flipSignBits(leftOperandRegIndex *ResultRegIndex, rightOperandRegIndex *ResultRegIndex) error {
+	if leftOperandRegIndex.isPointer {
+		return nil
+	}
+	if !emitter.isSigned(emitter.ctxNode.Children[0]) && !emitter.isSigned(emitter.ctxNode.Children[1]) {
+		return nil
+	}
+	err := emitter.saveOpcode(I7XKK(leftOperandRegIndex.lowBitsIndex, 0x80))
+	if err != nil {
+		return err
+	}
+	return emitter.saveOpcode(I7XKK(rightOperandRegIndex.lowBitsIndex, 0x80))
+}
+
+//compareHighBits compares the high bits of two words or pointers with a > (or a <), and saves the result in carry (vf).
+//If they are different the result of the comparison is already known, so it jumps to the end of the comparison, where
+//the result is taken from carry. Returns the addresses of the jumps, which are written once the end is known,
+//and an error if needed
+func (emitter *Emitter) compareHighBits(leftOperandRegIndex *ResultRegIndex, rightOperandRegIndex *ResultRegIndex) ([]uint16, error) {
+	//we compare a copy of vx0, so we can still ask if vx0 == vy0
+	err := emitter.saveOpcode(I8XY0(0, leftOperandRegIndex.highBitsIndex)) //v0 = vx0
+	if err != nil {
+		return nil, err
+	}
+	switch emitter.ctxNode.Value.Type {
+	case token.GT, token.GTEQ:
+		err = emitter.saveOpcode(I8XY5(0, rightOperandRegIndex.highBitsIndex))
+	case token.LT, token.LTEQ:
+		err = emitter.saveOpcode(I8XY7(0, rightOperandRegIndex.highBitsIndex))
+	default:
+		return nil, errors.New(errorhandler.UnexpectedCompilerError())
+	}
+	if err != nil {
+		return nil, err
+	}
+	//if carry = true the result is true, and we jump to the end
+	err = emitter.saveOpcode(I3XKK(Carry, False))
+	if err != nil {
+		return nil, err
+	}
+	jumps, err := emitter.reserveJump(nil)
+	if err != nil {
+		return nil, err
+	}
+	//if carry = false we ask if vx0 == vy0 with a xor, if they are different the result is false and we jump to the end
+	err = emitter.saveOpcode(I8XY3(leftOperandRegIndex.highBitsIndex, rightOperandRegIndex.highBitsIndex))
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.saveOpcode(I3XKK(leftOperandRegIndex.highBitsIndex, 0))
+	if err != nil {
+		return nil, err
+	}
+	return emitter.reserveJump(jumps)
+}
+
+//ltgteq translates <= and >= to opcodes and write it in emitter.machineCode,
+//returns the index of register in which the result is stored and an error
+func (emitter *Emitter) ltgteq(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	leftOperandRegIndex, rightOperandRegIndex, err := emitter.solveOperands(functionCtx)
+	if err != nil {
+		return nil, err
+	}
+	leftOperandRegIndex, rightOperandRegIndex, err = emitter.balanceOperands(functionCtx, leftOperandRegIndex, rightOperandRegIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	resultRegIndex, ok := functionCtx.registerHandler.AllocSimple() //the result is a bool
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		err := errors.New(errorhandler.TooManyRegisters(line))
+		return nil, err
+	}
+	err = emitter.flipSignBits(leftOperandRegIndex, rightOperandRegIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	//if we are comparing words or pointers, the low bits are only compared if the high bits are equal
+	var jumps []uint16
+	if leftOperandRegIndex.isPointer {
+		jumps, err = emitter.compareHighBits(leftOperandRegIndex, rightOperandRegIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = emitter.saveOpcode(I6XKK(Carry, True)) //vf = 1
+	if err != nil {
+		return nil, err
+	}
+	//we backup vx in v0
+	err = emitter.saveOpcode(I8XY0(0, leftOperandRegIndex.lowBitsIndex)) //v0=vx
+	if err != nil {
+		return nil, err
+	}
+
+	err = emitter.saveOpcode(I8XY3(0,
+		rightOperandRegIndex.lowBitsIndex)) //we ask v0 == vy with a xor
+	if err != nil {
+		return nil, err
+	}
+
+	err = emitter.saveOpcode(I3XKK(0, 0)) //if v0 = 0 then vx was equal to vy and we skip the next opcode
+	if err != nil {
+		return nil, err
+	}
+	switch emitter.ctxNode.Value.Type {
+	case token.GTEQ:
+		err = emitter.saveOpcode(I8XY5(leftOperandRegIndex.lowBitsIndex,
+			rightOperandRegIndex.lowBitsIndex)) //if vx wasn't equal to vy, we ask if vx > vy and store the result in vf
 		if err != nil {
 			return nil, err
 		}
+	case token.LTEQ:
+		err = emitter.saveOpcode(I8XY7(leftOperandRegIndex.lowBitsIndex,
+			rightOperandRegIndex.lowBitsIndex)) //if vx wasn't equal to vy, we ask if vx < vy and store the result in vf
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New(errorhandler.UnexpectedCompilerError())
+
+	}
+	emitter.writeJumps(jumps)
+
+	//then we save the result in a new register
+	err = emitter.saveOpcode(I8XY0(resultRegIndex.lowBitsIndex, Carry))
+	if err != nil {
+		return nil, err
+	}
+
+	functionCtx.registerHandler.Free(leftOperandRegIndex)
+	functionCtx.registerHandler.Free(rightOperandRegIndex)
+	return resultRegIndex, nil
+}
+
+//noteq translates a != to opcodes and write it in emitter.machineCode,
+//returns the index of register in which the result is stored and an error
+func (emitter *Emitter) noteq(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	datatype, isAnOperand := emitter.dataTypeOf(emitter.ctxNode.Children[0])
+	if isAnOperand && symboltable.IsAnArray(datatype) {
+		return emitter.compareArrays(functionCtx, symboltable.GetSize(datatype), False)
+	}
+	resultRegIndex, ok := functionCtx.registerHandler.AllocSimple() //the result is a bool
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		err := errors.New(errorhandler.TooManyRegisters(line))
+		return nil, err
+	}
+
+	leftOperandRegIndex, rightOperandRegIndex, constantOperand, err := emitter.solveOperandsOrConstant(functionCtx)
+	if err != nil {
+		return nil, err
+	}
+	if rightOperandRegIndex == nil {
+		//we set vz = false and skip vz = true if vx == constant
+		skip := I3XKK(leftOperandRegIndex.lowBitsIndex, constantOperand)
+		err = emitter.compareWithConstant(functionCtx, resultRegIndex, leftOperandRegIndex, skip)
+		return resultRegIndex, err
+	}
+	err = emitter.notEqual(functionCtx, resultRegIndex, leftOperandRegIndex, rightOperandRegIndex)
+	if err != nil {
+		return nil, err
+	}
+	return resultRegIndex, nil
+
+}
+
+//notEqual sets vz = true if the values of two operands saved in registers are different, or vz = false if not,
+//where vz is the register of the result. It frees the registers of the operands and returns an error if needed
+func (emitter *Emitter) notEqual(functionCtx *FunctionCtx, resultRegIndex *ResultRegIndex, leftOperandRegIndex *ResultRegIndex, rightOperandRegIndex *ResultRegIndex) error {
+	leftOperandRegIndex, rightOperandRegIndex, err := emitter.balanceOperands(functionCtx, leftOperandRegIndex, rightOperandRegIndex)
+	if err != nil {
+		return err
+	}
+
+	//if the operands are simple data types we do a xor between vx and vy,
+	//if they are equal, vx = 0
+	err = emitter.saveOpcode(I8XY3(leftOperandRegIndex.lowBitsIndex,
+		rightOperandRegIndex.lowBitsIndex))
+	if err != nil {
+		return err
+	}
+
+	if leftOperandRegIndex.isPointer {
+		//if not, we set vx0 = vx0 ^ vy0, vx1 = vx1 ^ vy1, vx1 = vx1 | vx0, vz= vx0
 		err = emitter.saveOpcode(I8XY3(leftOperandRegIndex.highBitsIndex,
-			rightOperandRegIndex.highBitsIndex)) //we ask vx0 == vyx with a xor. vx0 = 0 if true
+			rightOperandRegIndex.highBitsIndex))
+		if err != nil {
+			return err
+		}
+		err = emitter.saveOpcode(I8XY1(leftOperandRegIndex.lowBitsIndex,
+			leftOperandRegIndex.highBitsIndex))
 		if err != nil {
+			return err
+		}
+
+	}
+	//vx is 0 only if the operands are equal, so we set vz = false and skip vz = true if vx == 0
+	err = emitter.saveOpcode(I6XKK(resultRegIndex.lowBitsIndex, False))
+	if err != nil {
+		return err
+	}
+	err = emitter.saveOpcode(I3XKK(leftOperandRegIndex.lowBitsIndex, 0))
+	if err != nil {
+		return err
+	}
+	err = emitter.saveOpcode(I6XKK(resultRegIndex.lowBitsIndex, True))
+	if err != nil {
+		return err
+	}
+	functionCtx.registerHandler.Free(leftOperandRegIndex)
+	functionCtx.registerHandler.Free(rightOperandRegIndex)
+	return nil
+}
+
+//eqeq translates a == to opcodes and write it in emitter.machineCode,
+//returns the index of register in which the result is stored and an error
+func (emitter *Emitter) eqeq(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	datatype, isAnOperand := emitter.dataTypeOf(emitter.ctxNode.Children[0])
+	if isAnOperand && symboltable.IsAnArray(datatype) {
+		return emitter.compareArrays(functionCtx, symboltable.GetSize(datatype), True)
+	}
+	var regIndex *ResultRegIndex
+	constantOperand, size, isConstant := constant.EvaluateWithSize(emitter.ctxNode.Children[1], emitter.scope)
+	if isConstant {
+		resultRegIndex, ok := functionCtx.registerHandler.AllocSimple() //the result is a bool
+		if !ok {
+			line := emitter.ctxNode.Value.Line
+			err := errors.New(errorhandler.TooManyRegisters(line))
 			return nil, err
 		}
-		err = emitter.saveOpcode(I3XKK(leftOperandRegIndex.highBitsIndex, 0)) //if vx0 = 0 we skip  the next opcode
+		emitter.ctxNode = emitter.ctxNode.Children[0]
+		leftOperandRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
 		if err != nil {
 			return nil, err
 		}
-		err = emitter.saveOpcode(I1NNN(emitter.currentAddress + 6*2)) //if vx0 != 0 we skip 6 opcodes because we know the result is false
+		if !leftOperandRegIndex.isPointer && size == 1 {
+			//if both are bytes, we set vz = false and skip vz = true if vx != constant
+			skip := I4XKK(leftOperandRegIndex.lowBitsIndex, byte(constantOperand))
+			err = emitter.compareWithConstant(functionCtx, resultRegIndex, leftOperandRegIndex, skip)
+			return resultRegIndex, err
+		}
+		//words are compared with the constant in registers, as in !=
+		rightOperandRegIndex, err := emitter.loadConstant(functionCtx, constantOperand, 2)
+		if err != nil {
+			return nil, err
+		}
+		err = emitter.notEqual(functionCtx, resultRegIndex, leftOperandRegIndex, rightOperandRegIndex)
+		if err != nil {
+			return nil, err
+		}
+		regIndex = resultRegIndex
+	} else {
+		//we do the same than in !=, but with a not at the end
+		var err error
+		regIndex, err = emitter.noteq(functionCtx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	aux := byte(0)
+	err := emitter.saveOpcode(I6XKK(aux, True)) //aux=true
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.saveOpcode(I8XY3(regIndex.lowBitsIndex, aux)) // vx = vx ^ true
+	if err != nil {
+		return nil, err
+	}
+
+	return regIndex, nil
+
+}
+
+//compareArrays translates a comparison between two arrays to opcodes and write it in emitter.machineCode. The arrays are
+//compared in blocks of SizeCompareBlock bytes: a block of the left array is loaded and moved to the upper half of the
+//registers, then the block of the right array is loaded and each pair of bytes is compared, jumping out on the first difference.
+//The result is "equal" if the arrays are equal and its negation if not.
+//Returns the index of register in which the result is stored and an error
+func (emitter *Emitter) compareArrays(functionCtx *FunctionCtx, size int, equal byte) (*ResultRegIndex, error) {
+	const LEFT = 0
+	const RIGHT = 1
+	comparisonBackup := emitter.ctxNode
+	resultRegIndex, ok := functionCtx.registerHandler.AllocSimple() //the result is a bool
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		err := errors.New(errorhandler.TooManyRegisters(line))
+		return nil, err
+	}
+	err := emitter.spillRegisters()
+	if err != nil {
+		return nil, err
+	}
+	blocks := (size + SizeCompareBlock - 1) / SizeCompareBlock
+	emitter.ctxNode = comparisonBackup.Children[LEFT]
+	leftPatches, err := emitter.reserveBlockAddresses(functionCtx, blocks, SizeCompareBlock)
+	if err != nil {
+		return nil, err
+	}
+	emitter.ctxNode = comparisonBackup.Children[RIGHT]
+	rightPatches, err := emitter.reserveBlockAddresses(functionCtx, blocks, SizeCompareBlock)
+	if err != nil {
+		return nil, err
+	}
+	emitter.ctxNode = comparisonBackup
+
+	differenceJumps := make([]uint16, 0)
+	for i := 0; i < blocks; i++ {
+		block := byte(size - i*SizeCompareBlock)
+		if block > SizeCompareBlock {
+			block = SizeCompareBlock
+		}
+		err = emitter.writeBlockAddress(leftPatches[i]) //I = address of the block of the left array
 		if err != nil {
 			return nil, err
 		}
-		err = emitter.saveOpcode(I8XY3(1, 2)) //we ask v1 == vy1 with a xor. v1 = 0 if tre
+		err = emitter.saveOpcode(IFX65(block - 1))
 		if err != nil {
 			return nil, err
 		}
-		// if v1 = 0 we set carry = 1 and jump to the end
-		err = emitter.saveOpcode(I4XKK(1, 0))
-		if err != nil {
-			return nil, err
+		for j := byte(0); j < block; j++ {
+			err = emitter.saveOpcode(I8XY0(j+SizeCompareBlock, j))
+			if err != nil {
+				return nil, err
+			}
 		}
-		err = emitter.saveOpcode(I6XKK(Carry, True)) //vf = 1
+		err = emitter.writeBlockAddress(rightPatches[i]) //I = address of the block of the right array
 		if err != nil {
 			return nil, err
 		}
-		err = emitter.saveOpcode(I3XKK(1, 0))
+		err = emitter.saveOpcode(IFX65(block - 1))
 		if err != nil {
 			return nil, err
 		}
-		//if v1 != we ask if vx1 is greater/lesser than vy1 and store the result in vf
-		switch emitter.ctxNode.Value.Type {
-		case token.GTEQ:
-			err = emitter.saveOpcode(I8XY5(leftOperandRegIndex.lowBitsIndex,
-				rightOperandRegIndex.lowBitsIndex))
+		for j := byte(0); j < block; j++ {
+			//if the bytes are equal we skip the jump out
+			err = emitter.saveOpcode(I5XY0(j, j+SizeCompareBlock))
 			if err != nil {
 				return nil, err
 			}
-		case token.LTEQ:
-			err = emitter.saveOpcode(I8XY7(leftOperandRegIndex.lowBitsIndex,
-				rightOperandRegIndex.lowBitsIndex))
+			differenceJumps, err = emitter.reserveJump(differenceJumps)
 			if err != nil {
 				return nil, err
 			}
-		default:
-			return nil, errors.New(errorhandler.UnexpectedCompilerError())
-
-		}
-		//we set vz = vf
-		err = emitter.saveOpcode(I8XY0(resultRegIndex.lowBitsIndex, Carry))
-		if err != nil {
-			return nil, err
 		}
+	}
 
+	//if we didn't jump out the arrays are equal
+	err = emitter.restoreRegisters()
+	if err != nil {
+		return nil, err
 	}
-	functionCtx.registerHandler.Free(leftOperandRegIndex)
-	functionCtx.registerHandler.Free(rightOperandRegIndex)
+	err = emitter.saveOpcode(I6XKK(resultRegIndex.lowBitsIndex, equal))
+	if err != nil {
+		return nil, err
+	}
+	endJump, err := emitter.reserveJump(make([]uint16, 0))
+	if err != nil {
+		return nil, err
+	}
+	emitter.writeJumps(differenceJumps)
+	err = emitter.restoreRegisters()
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.saveOpcode(I6XKK(resultRegIndex.lowBitsIndex, equal^True))
+	if err != nil {
+		return nil, err
+	}
+	emitter.writeJumps(endJump)
 	return resultRegIndex, nil
 }
 
-//noteq translates a != to opcodes and write it in emitter.machineCode,
+//not translates a ! to opcodes and write it in emitter.machineCode,
 //returns the index of register in which the result is stored and an error
-func (emitter *Emitter) noteq(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	resultRegIndex, ok := functionCtx.registerHandler.AllocSimple() //the result is a bool
-	if !ok {
-		line := emitter.ctxNode.Value.Line
-		err := errors.New(errorhandler.TooManyRegisters(line))
+func (emitter *Emitter) not(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	emitter.ctxNode = emitter.ctxNode.Children[0]
+	childRegisterIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
+	if err != nil {
+		return nil, err
+	}
+	//we set vx = vx ^ true
+	err = emitter.saveOpcode(I6XKK(0, True)) //we use v0 as auxiliary, v0 = true
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.saveOpcode(I8XY3(childRegisterIndex.lowBitsIndex, 0))
+	if err != nil {
 		return nil, err
 	}
 
-	leftOperandRegIndex, rightOperandRegIndex, err := emitter.solveOperands(functionCtx)
+	return childRegisterIndex, nil
+
+}
+
+//conditional translates a conditional expression to opcodes and write it in emitter.machineCode. Only the branch chosen by
+//the condition is evaluated, and both of them leave their value in the same registers.
+//Returns the indexes of registers in which the result is stored and an error
+func (emitter *Emitter) conditional(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	const CONDITION = 0
+	const BRANCHES = 1
+	backup := emitter.ctxNode
+	emitter.ctxNode = backup.Children[CONDITION]
+	conditionRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.saveOpcode(I4XKK(conditionRegIndex.lowBitsIndex, False)) //if vx = true (or vx != false) we skip the next instruction
+	if err != nil {
+		return nil, err
+	}
+	functionCtx.registerHandler.Free(conditionRegIndex)
+	//the next instruction is a jump to the false branch, because we don't know its address yet,
+	//we save the current address to write the opcode later
+	lineAfterCondition := emitter.currentAddress
+	err = emitter.moveCurrentAddress()
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.moveCurrentAddress()
 	if err != nil {
 		return nil, err
 	}
 
-	//if the operands are simple data types we do a xor between vx and vy,
-	//if they are equal, vx = 0
-	err = emitter.saveOpcode(I8XY3(leftOperandRegIndex.lowBitsIndex,
-		rightOperandRegIndex.lowBitsIndex))
+	branches := backup.Children[BRANCHES]
+	emitter.ctxNode = branches.Children[0]
+	resultRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
+	if err != nil {
+		return nil, err
+	}
+	//after the true branch we jump the false one
+	lineAfterTrueBranch := emitter.currentAddress
+	err = emitter.moveCurrentAddress()
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.moveCurrentAddress()
 	if err != nil {
 		return nil, err
 	}
+	i1nnn := I1NNN(emitter.currentAddress)
+	emitter.machineCode[lineAfterCondition] = i1nnn[0]
+	emitter.machineCode[lineAfterCondition+1] = i1nnn[1]
 
-	if leftOperandRegIndex.isPointer {
-		//if not, we set vx0 = vx0 ^ vy0, vx1 = vx1 ^ vy1, vx1 = vx1 | vx0, vz= vx0
-		err = emitter.saveOpcode(I8XY3(leftOperandRegIndex.highBitsIndex,
-			rightOperandRegIndex.highBitsIndex))
+	emitter.ctxNode = branches.Children[1]
+	falseRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
+	if err != nil {
+		return nil, err
+	}
+	//we copy the value of the false branch in the registers of the true one
+	err = emitter.saveOpcode(I8XY0(resultRegIndex.lowBitsIndex, falseRegIndex.lowBitsIndex))
+	if err != nil {
+		return nil, err
+	}
+	if resultRegIndex.isPointer {
+		if falseRegIndex.isPointer {
+			err = emitter.saveOpcode(I8XY0(resultRegIndex.highBitsIndex, falseRegIndex.highBitsIndex))
+		} else {
+			//if only the true branch is a word, the false one is widened
+			err = emitter.saveOpcode(I6XKK(resultRegIndex.highBitsIndex, 0))
+		}
 		if err != nil {
 			return nil, err
 		}
-		err = emitter.saveOpcode(I8XY1(leftOperandRegIndex.lowBitsIndex,
-			leftOperandRegIndex.highBitsIndex))
+	} else if falseRegIndex.isPointer {
+		//if only the false branch is a word, the true one is widened: it jumps to an opcode that clears the high bits,
+		//which the false branch skips
+		highBitsRegIndex, ok := functionCtx.registerHandler.AllocSimple()
+		if !ok {
+			line := emitter.ctxNode.Value.Line
+			return nil, errors.New(errorhandler.TooManyRegisters(line))
+		}
+		err = emitter.saveOpcode(I8XY0(highBitsRegIndex.lowBitsIndex, falseRegIndex.highBitsIndex))
 		if err != nil {
 			return nil, err
 		}
-
-	}
-	err = emitter.saveOpcode(I8XY0(resultRegIndex.lowBitsIndex,
-		leftOperandRegIndex.lowBitsIndex))
-	if err != nil {
-		return nil, err
+		lineAfterFalseBranch := emitter.currentAddress
+		err = emitter.moveCurrentAddress()
+		if err != nil {
+			return nil, err
+		}
+		err = emitter.moveCurrentAddress()
+		if err != nil {
+			return nil, err
+		}
+		i1nnn = I1NNN(emitter.currentAddress)
+		emitter.machineCode[lineAfterTrueBranch] = i1nnn[0]
+		emitter.machineCode[lineAfterTrueBranch+1] = i1nnn[1]
+		err = emitter.saveOpcode(I6XKK(highBitsRegIndex.lowBitsIndex, 0))
+		if err != nil {
+			return nil, err
+		}
+		lineAfterTrueBranch = lineAfterFalseBranch
+		resultRegIndex = &ResultRegIndex{highBitsIndex: highBitsRegIndex.lowBitsIndex,
+			lowBitsIndex: resultRegIndex.lowBitsIndex, isPointer: true}
 	}
-	functionCtx.registerHandler.Free(leftOperandRegIndex)
-	functionCtx.registerHandler.Free(rightOperandRegIndex)
+	functionCtx.registerHandler.Free(falseRegIndex)
+	i1nnn = I1NNN(emitter.currentAddress)
+	emitter.machineCode[lineAfterTrueBranch] = i1nnn[0]
+	emitter.machineCode[lineAfterTrueBranch+1] = i1nnn[1]
+
+	emitter.ctxNode = backup
 	return resultRegIndex, nil
+}
 
+//minus translates a negation or a subtraction, depending on the number of children of the minus
+func (emitter *Emitter) minus(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	if len(emitter.ctxNode.Children) == 1 {
+		return emitter.negation(functionCtx)
+	} else {
+		return emitter.subtraction(functionCtx)
+	}
 }
 
-//eqeq translates a == to opcodes and write it in emitter.machineCode,
+//negation translates an unary - to opcodes and write it in emitter.machineCode,
 //returns the index of register in which the result is stored and an error
-func (emitter *Emitter) eqeq(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	//we do the same than in !=, but with a not at the end
-	regIndex, err := emitter.noteq(functionCtx)
+func (emitter *Emitter) negation(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	emitter.ctxNode = emitter.ctxNode.Children[0]
+	childRegisterIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
 	if err != nil {
 		return nil, err
 	}
-	aux := byte(0)
-	err = emitter.saveOpcode(I6XKK(aux, True)) //aux=true
+	//we set vx = 0 - vx
+	err = emitter.saveOpcode(I6XKK(0, 0)) //we use v0 as auxiliary, v0 = 0
 	if err != nil {
 		return nil, err
 	}
-	err = emitter.saveOpcode(I8XY3(regIndex.lowBitsIndex, aux)) // vx = vx ^ true
+	err = emitter.saveOpcode(I8XY7(childRegisterIndex.lowBitsIndex, 0))
 	if err != nil {
 		return nil, err
 	}
 
-	return regIndex, nil
-
+	return childRegisterIndex, nil
 }
 
-//not translates a ! to opcodes and write it in emitter.machineCode,
+//complement translates a ~ to opcodes and write it in emitter.machineCode,
 //returns the index of register in which the result is stored and an error
-func (emitter *Emitter) not(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+func (emitter *Emitter) complement(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
 	emitter.ctxNode = emitter.ctxNode.Children[0]
 	childRegisterIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
 	if err != nil {
 		return nil, err
 	}
-	//we set vx = vx ^ true
-	err = emitter.saveOpcode(I6XKK(0, True)) //we use v0 as auxiliary, v0 = true
+	//we set vx = vx ^ 0xFF
+	err = emitter.saveOpcode(I6XKK(0, 0xFF)) //we use v0 as auxiliary, v0 = 0xFF
 	if err != nil {
 		return nil, err
 	}
@@ -1522,39 +2743,64 @@ func (emitter *Emitter) not(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
 	}
 
 	return childRegisterIndex, nil
-
 }
 
-//land translates a && to opcodes and write it in emitter.machineCode,
-//returns the index of register in which the result is stored and an error
+//land translates a && to opcodes and write it in emitter.machineCode, the right operand is only evaluated if the left one is true.
+//Returns the index of register in which the result is stored and an error
 func (emitter *Emitter) land(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	leftOperandRegIndex, rightOperandRegIndex, err := emitter.solveOperands(functionCtx)
+	//if vx = true (or vx != false) we skip the jump that avoids the evaluation of the right operand
+	return emitter.shortCircuit(functionCtx, I4XKK)
+}
+
+//lor translates a || to opcodes and write it in emitter.machineCode, the right operand is only evaluated if the left one is false.
+//Returns the index of register in which the result is stored and an error
+func (emitter *Emitter) lor(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	//if vx = false we skip the jump that avoids the evaluation of the right operand
+	return emitter.shortCircuit(functionCtx, I3XKK)
+}
+
+//shortCircuit translates a logical operation whose result is the left operand, unless skipJump(vx, false) skips the jump
+//after it, in which case the result is the right operand.
+//Returns the index of register in which the result is stored and an error
+func (emitter *Emitter) shortCircuit(functionCtx *FunctionCtx, skipJump func(x byte, kk byte) Opcode) (*ResultRegIndex, error) {
+	backup := emitter.ctxNode
+	emitter.ctxNode = emitter.ctxNode.Children[0]
+	leftOperandRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
 	if err != nil {
 		return nil, err
 	}
-	//Vx = Vx & Vy
-	err = emitter.saveOpcode(I8XY2(leftOperandRegIndex.lowBitsIndex, rightOperandRegIndex.lowBitsIndex))
+	err = emitter.saveOpcode(skipJump(leftOperandRegIndex.lowBitsIndex, False))
 	if err != nil {
 		return nil, err
 	}
-	functionCtx.registerHandler.Free(rightOperandRegIndex)
-
-	return leftOperandRegIndex, nil
-}
-
-//lor translates a || to opcodes and write it in emitter.machineCode,
-//returns the index of register in which the result is stored and an error
-func (emitter *Emitter) lor(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	leftOperandRegIndex, rightOperandRegIndex, err := emitter.solveOperands(functionCtx)
+	//the next instruction is a jump to the memory address after the right operand
+	//because we don't know this address yet, we save the current address to write the opcode later
+	lineAfterLeftOperand := emitter.currentAddress
+	err = emitter.moveCurrentAddress()
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.moveCurrentAddress()
+	if err != nil {
+		return nil, err
+	}
+	emitter.ctxNode = backup
+	emitter.ctxNode = emitter.ctxNode.Children[1]
+	rightOperandRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
 	if err != nil {
 		return nil, err
 	}
-	//VX = VX | VY
-	err = emitter.saveOpcode(I8XY1(leftOperandRegIndex.lowBitsIndex, rightOperandRegIndex.lowBitsIndex))
+	//Vx = Vy
+	err = emitter.saveOpcode(I8XY0(leftOperandRegIndex.lowBitsIndex, rightOperandRegIndex.lowBitsIndex))
 	if err != nil {
 		return nil, err
 	}
 	functionCtx.registerHandler.Free(rightOperandRegIndex)
+	emitter.ctxNode = backup
+
+	i1nnn := I1NNN(emitter.currentAddress)
+	emitter.machineCode[lineAfterLeftOperand] = i1nnn[0]
+	emitter.machineCode[lineAfterLeftOperand+1] = i1nnn[1]
 
 	return leftOperandRegIndex, nil
 }
@@ -1566,6 +2812,10 @@ func (emitter *Emitter) or(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
 	if err != nil {
 		return nil, err
 	}
+	leftOperandRegIndex, rightOperandRegIndex, err = emitter.balanceOperands(functionCtx, leftOperandRegIndex, rightOperandRegIndex)
+	if err != nil {
+		return nil, err
+	}
 	//Vx1 = Vx1 | Vy1
 	err = emitter.saveOpcode(I8XY1(leftOperandRegIndex.lowBitsIndex,
 		rightOperandRegIndex.lowBitsIndex))
@@ -1594,6 +2844,10 @@ func (emitter *Emitter) and(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
 	if err != nil {
 		return nil, err
 	}
+	leftOperandRegIndex, rightOperandRegIndex, err = emitter.balanceOperands(functionCtx, leftOperandRegIndex, rightOperandRegIndex)
+	if err != nil {
+		return nil, err
+	}
 	//Vx1 = Vx1 & Vy1
 	err = emitter.saveOpcode(I8XY2(leftOperandRegIndex.lowBitsIndex,
 		rightOperandRegIndex.lowBitsIndex))
@@ -1621,6 +2875,10 @@ func (emitter *Emitter) xor(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
 	if err != nil {
 		return nil, err
 	}
+	leftOperandRegIndex, rightOperandRegIndex, err = emitter.balanceOperands(functionCtx, leftOperandRegIndex, rightOperandRegIndex)
+	if err != nil {
+		return nil, err
+	}
 	//Vx1 = Vx1 ^ Vy1
 	err = emitter.saveOpcode(I8XY3(leftOperandRegIndex.lowBitsIndex,
 		rightOperandRegIndex.lowBitsIndex))
@@ -1645,14 +2903,29 @@ func (emitter *Emitter) xor(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
 //sum translates a sum to opcodes and write it in emitter.machineCode,
 //returns the indexes of registers in which the result is stored  and an error
 func (emitter *Emitter) sum(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	leftRegIndex, rightRegIndex, err := emitter.solveOperands(functionCtx)
+	leftRegIndex, rightRegIndex, constantOperand, err := emitter.solveOperandsOrConstant(functionCtx)
 	if err != nil {
 		return nil, err
 	}
+	if rightRegIndex == nil {
+		//we add the constant directly to vLeft
+		err = emitter.saveOpcode(I7XKK(leftRegIndex.lowBitsIndex, constantOperand))
+		if err != nil {
+			return nil, err
+		}
+		return leftRegIndex, nil
+	}
+	//if a byte is added to a word, the byte is widened
+	if !leftRegIndex.isPointer && rightRegIndex.isPointer {
+		leftRegIndex, err = emitter.widen(functionCtx, leftRegIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	if leftRegIndex.isPointer {
 
-		//if the left operands is a pointer we first sum vLeft1 = vLeft1 + vRight
+		//if the left operands is a pointer (or a word) we first sum vLeft1 = vLeft1 + vRight
 		err = emitter.saveOpcode(I8XY4(leftRegIndex.lowBitsIndex, rightRegIndex.lowBitsIndex))
 		if err != nil {
 			return nil, err
@@ -1666,6 +2939,13 @@ func (emitter *Emitter) sum(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
 		if err != nil {
 			return nil, err
 		}
+		//if the right operand is a word, we also sum vLeft0 = vLeft0 + vRight0
+		if rightRegIndex.isPointer {
+			err = emitter.saveOpcode(I8XY4(leftRegIndex.highBitsIndex, rightRegIndex.highBitsIndex))
+			if err != nil {
+				return nil, err
+			}
+		}
 
 		functionCtx.registerHandler.Free(rightRegIndex)
 
@@ -1688,10 +2968,25 @@ func (emitter *Emitter) sum(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
 //subtraction translates a subtraction to opcodes and write it in emitter.machineCode,
 //returns the indexes of the registers in which the result was stored and an error if needed
 func (emitter *Emitter) subtraction(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	leftOperandRegIndex, rightOperandRegIndex, err := emitter.solveOperands(functionCtx)
+	leftOperandRegIndex, rightOperandRegIndex, constantOperand, err := emitter.solveOperandsOrConstant(functionCtx)
 	if err != nil {
 		return nil, err
 	}
+	if rightOperandRegIndex == nil {
+		//subtracting the constant is the same than adding its two's complement, vx = vx + (256 - constant)
+		err = emitter.saveOpcode(I7XKK(leftOperandRegIndex.lowBitsIndex, -constantOperand))
+		if err != nil {
+			return nil, err
+		}
+		return leftOperandRegIndex, nil
+	}
+	//if a word is subtracted from a byte, the byte is widened
+	if !leftOperandRegIndex.isPointer && rightOperandRegIndex.isPointer {
+		leftOperandRegIndex, err = emitter.widen(functionCtx, leftOperandRegIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
 	//the result is going to be of the same data type that the left operand
 	if !leftOperandRegIndex.isPointer {
 		//if the left operand is a simple data type we just subtract vx = vx - vy, and save the result in a new register
@@ -1701,11 +2996,38 @@ func (emitter *Emitter) subtraction(functionCtx *FunctionCtx) (*ResultRegIndex,
 		}
 	} else {
 
-		//if the left operands is a pointer we first subtract vx1 = vx1 - vy
+		//if the left operands is a pointer (or a word) we first subtract vx1 = vx1 - vy
 		err = emitter.saveOpcode(I8XY5(leftOperandRegIndex.lowBitsIndex, rightOperandRegIndex.lowBitsIndex))
 		if err != nil {
 			return nil, err
 		}
+		//8XY5 only sets carry = true if vx1 > vy, but there is no borrow if they were equal either,
+		//in which case vx1 = 0 now, so we set carry = true if vx1 == 0
+		err = emitter.saveOpcode(I4XKK(leftOperandRegIndex.lowBitsIndex, 0))
+		if err != nil {
+			return nil, err
+		}
+		err = emitter.saveOpcode(I6XKK(Carry, True))
+		if err != nil {
+			return nil, err
+		}
+		if rightOperandRegIndex.isPointer {
+			//if both are words, we set vx0 = vx0 - 1 if carry = false, and then vx0 = vx0 - vy0
+			err = emitter.saveOpcode(I4XKK(Carry, False))
+			if err != nil {
+				return nil, err
+			}
+			err = emitter.saveOpcode(I7XKK(leftOperandRegIndex.highBitsIndex, 0xFF))
+			if err != nil {
+				return nil, err
+			}
+			err = emitter.saveOpcode(I8XY5(leftOperandRegIndex.highBitsIndex, rightOperandRegIndex.highBitsIndex))
+			if err != nil {
+				return nil, err
+			}
+			functionCtx.registerHandler.Free(rightOperandRegIndex)
+			return leftOperandRegIndex, nil
+		}
 		//because we already use vy, we can now use it as an aux, vy = 1
 		aux := rightOperandRegIndex.lowBitsIndex
 		err = emitter.saveOpcode(I6XKK(aux, 1))
@@ -1745,23 +3067,28 @@ func (emitter *Emitter) shift(functionCtx *FunctionCtx) (*ResultRegIndex, error)
 		return nil, err
 	}
 	//if vy =0, we skip the operation
-	skip := I1NNN(emitter.currentAddress + 5*2)
-	err = emitter.saveOpcode(skip)
+	skip, err := emitter.reserveJump(nil)
+	if err != nil {
+		return nil, err
+	}
+	loop := emitter.currentAddress
 	//we shift vx by 1
-	switch emitter.ctxNode.Value.Type {
-	case token.GTGT:
-		err = emitter.saveOpcode(I8XY6(leftOperandRegIndex.lowBitsIndex))
-		if err != nil {
-			return nil, err
-		}
-	case token.LTLT:
-		err = emitter.saveOpcode(I8XYE(leftOperandRegIndex.lowBitsIndex))
-		if err != nil {
-			return nil, err
+	if leftOperandRegIndex.isPointer {
+		err = emitter.shiftWord(leftOperandRegIndex)
+	} else if emitter.ctxNode.Value.Type == token.GTGT && emitter.isSigned(emitter.ctxNode.Children[0]) {
+		err = emitter.arithmeticShift(leftOperandRegIndex)
+	} else {
+		switch emitter.ctxNode.Value.Type {
+		case token.GTGT:
+			err = emitter.saveOpcode(I8XY6(leftOperandRegIndex.lowBitsIndex))
+		case token.LTLT:
+			err = emitter.saveOpcode(I8XYE(leftOperandRegIndex.lowBitsIndex))
+		default:
+			return nil, errors.New(errorhandler.UnexpectedCompilerError())
 		}
-	default:
-		return nil, errors.New(errorhandler.UnexpectedCompilerError())
-
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	//vy = vy - 1
@@ -1775,14 +3102,66 @@ func (emitter *Emitter) shift(functionCtx *FunctionCtx) (*ResultRegIndex, error)
 	if err != nil {
 		return nil, err
 	}
-	err = emitter.saveOpcode(I1NNN(emitter.currentAddress - 3*2))
-	if err != nil {
-		return nil, err
+	err = emitter.saveOpcode(I1NNN(loop))
+	if err != nil {
+		return nil, err
+	}
+	emitter.writeJumps(skip)
+
+	functionCtx.registerHandler.Free(rightOperandRegIndex)
+	return leftOperandRegIndex, nil
+
+}
+
+//shiftWord shifts a word saved in two registers by 1. The bit that goes out of one register is saved in carry (vf),
+//and copied in v1 to move it into the other one. Returns an error if needed
+func (emitter *Emitter) shiftWord(regIndex *ResultRegIndex) error {
+	var opcodes []Opcode
+	switch emitter.ctxNode.Value.Type {
+	case token.LTLT:
+		opcodes = []Opcode{
+			I8XYE(regIndex.lowBitsIndex),
+			I8XY0(1, Carry), //v1 = the highest bit of vx1
+			I8XYE(regIndex.highBitsIndex),
+			I8XY1(regIndex.highBitsIndex, 1), //vx0 = vx0 | v1
+		}
+	case token.GTGT:
+		opcodes = []Opcode{
+			I8XY6(regIndex.highBitsIndex),
+			I8XY0(1, Carry), //v1 = the lowest bit of vx0
+			I8XY6(regIndex.lowBitsIndex),
+			I3XKK(1, 0),                        //if v1 = 0 we skip the next opcode
+			I7XKK(regIndex.lowBitsIndex, 0x80), //if not, we set the highest bit of vx1
+		}
+	default:
+		return errors.New(errorhandler.UnexpectedCompilerError())
+	}
+	for _, opcode := range opcodes {
+		err := emitter.saveOpcode(opcode)
+		if err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	functionCtx.registerHandler.Free(rightOperandRegIndex)
-	return leftOperandRegIndex, nil
-
+//arithmeticShift shifts an int8 saved in a register by 1 to the right, keeping its sign: the highest bit is copied
+//from the value before the shift. Returns an error if needed
+func (emitter *Emitter) arithmeticShift(regIndex *ResultRegIndex) error {
+	opcodes := []Opcode{
+		I8XY0(1, regIndex.lowBitsIndex), //v1 = vx
+		I8XY6(regIndex.lowBitsIndex),
+		I8XYE(1),                           //vf = the highest bit of v1
+		I3XKK(Carry, 0),                    //if vf = 0 we skip the next opcode
+		I7XKK(regIndex.lowBitsIndex, 0x80), //if not, we set the highest bit of vx
+	}
+	for _, opcode := range opcodes {
+		err := emitter.saveOpcode(opcode)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 //multiplication translates a multiplication to opcodes and write it in emitter.machineCode,
@@ -2055,6 +3434,163 @@ func (emitter *Emitter) division(functionCtx *FunctionCtx) (*ResultRegIndex, err
 	return leftOperandRegIndex, nil
 }
 
+//compareWithConstant sets vz = false, writes the skip opcode that compares vx with a constant and then sets vz = true.
+//It frees the register of the operand and returns an error if needed
+func (emitter *Emitter) compareWithConstant(functionCtx *FunctionCtx, resultRegIndex *ResultRegIndex, operandRegIndex *ResultRegIndex, skip Opcode) error {
+	err := emitter.saveOpcode(I6XKK(resultRegIndex.lowBitsIndex, False))
+	if err != nil {
+		return err
+	}
+	err = emitter.saveOpcode(skip)
+	if err != nil {
+		return err
+	}
+	err = emitter.saveOpcode(I6XKK(resultRegIndex.lowBitsIndex, True))
+	if err != nil {
+		return err
+	}
+	functionCtx.registerHandler.Free(operandRegIndex)
+	return nil
+}
+
+//solveOperandsOrConstant works as solveOperands, unless the right operand is a constant byte and the left one is a byte.
+//In that case it only saves the left operand in registers and returns the value of the constant instead of the index
+//of the registers of the right operand, which is nil
+func (emitter *Emitter) solveOperandsOrConstant(functionCtx *FunctionCtx) (*ResultRegIndex, *ResultRegIndex, byte, error) {
+	value, size, isConstant := constant.EvaluateWithSize(emitter.ctxNode.Children[1], emitter.scope)
+	if !isConstant {
+		leftOperandRegIndex, rightOperandRegIndex, err := emitter.solveOperands(functionCtx)
+		return leftOperandRegIndex, rightOperandRegIndex, 0, err
+	}
+	backup := emitter.ctxNode
+	emitter.ctxNode = emitter.ctxNode.Children[0]
+	leftOperandRegIndex, err := emitter.translateOperand(functionCtx)
+	emitter.ctxNode = backup
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if !leftOperandRegIndex.isPointer && size == 1 {
+		return leftOperandRegIndex, nil, byte(value), nil
+	}
+
+	//the operations between pointers and bytes need the byte in a register, as well as the ones with words
+	rightOperandRegIndex, err := emitter.loadConstant(functionCtx, value, size)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return leftOperandRegIndex, rightOperandRegIndex, 0, nil
+}
+
+//foldConstant wraps the translation of an operation, so when the value of the operation is known at compile time
+//it's saved in a register with a single opcode instead. Returns the index of the register and an error if needed
+func (emitter *Emitter) foldConstant(translate func(*FunctionCtx) (*ResultRegIndex, error)) func(*FunctionCtx) (*ResultRegIndex, error) {
+	return func(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+		value, size, isConstant := constant.EvaluateWithSize(emitter.ctxNode, emitter.scope)
+		if !isConstant {
+			return translate(functionCtx)
+		}
+		return emitter.loadConstant(functionCtx, value, size)
+	}
+}
+
+//loadConstant saves a constant in a register, or in two if its size is 2. Returns the index of the registers and an error if needed
+func (emitter *Emitter) loadConstant(functionCtx *FunctionCtx, value int, size int) (*ResultRegIndex, error) {
+	var regIndex *ResultRegIndex
+	var ok bool
+	if size == 2 {
+		regIndex, ok = functionCtx.registerHandler.AllocPointer()
+	} else {
+		regIndex, ok = functionCtx.registerHandler.AllocSimple()
+	}
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		err := errors.New(errorhandler.TooManyRegisters(line))
+		return nil, err
+	}
+	if regIndex.isPointer {
+		err := emitter.saveOpcode(I6XKK(regIndex.highBitsIndex, byte(value>>8))) // Vx0 = high bits of the value
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := emitter.saveOpcode(I6XKK(regIndex.lowBitsIndex, byte(value))) // Vx = value
+	if err != nil {
+		return nil, err
+	}
+	return regIndex, nil
+}
+
+//widen saves a byte in two registers as a word, whose high bits are 0. It only allocates a register for the high bits,
+//the low ones are the register of the byte. Returns the indexes of the registers and an error if needed
+func (emitter *Emitter) widen(functionCtx *FunctionCtx, regIndex *ResultRegIndex) (*ResultRegIndex, error) {
+	highBitsRegIndex, ok := functionCtx.registerHandler.AllocSimple()
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		err := errors.New(errorhandler.TooManyRegisters(line))
+		return nil, err
+	}
+	err := emitter.saveOpcode(I6XKK(highBitsRegIndex.lowBitsIndex, 0))
+	if err != nil {
+		return nil, err
+	}
+	return &ResultRegIndex{highBitsIndex: highBitsRegIndex.lowBitsIndex, lowBitsIndex: regIndex.lowBitsIndex, isPointer: true}, nil
+}
+
+//signExtend saves an int8 in two registers as a word, whose high bits are 0xFF if the int8 is negative and 0 if not.
+//It only allocates a register for the high bits, the low ones are the register of the int8.
+//Returns the indexes of the registers and an error if needed
+func (emitter *Emitter) signExtend(functionCtx *FunctionCtx, regIndex *ResultRegIndex) (*ResultRegIndex, error) {
+	wordRegIndex, err := emitter.widen(functionCtx, regIndex)
+	if err != nil {
+		return nil, err
+	}
+	opcodes := []Opcode{
+		I8XY0(1, regIndex.lowBitsIndex), //v1 = vx
+		I8XYE(1),                        //vf = the highest bit of v1
+		I3XKK(Carry, 0),                 //if vf = 0 we skip the next opcode
+		I6XKK(wordRegIndex.highBitsIndex, 0xFF),
+	}
+	for _, opcode := range opcodes {
+		err = emitter.saveOpcode(opcode)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return wordRegIndex, nil
+}
+
+//isAType checks if an identifier is the name of a type, which leads a conversion instead of a call
+func (emitter *Emitter) isAType(ident *ast.Node) bool {
+	symbol, ok := emitter.scope.Symbols[ident.Value.Literal]
+	return ok && symbol.IsType
+}
+
+//isSigned checks if the value of the expression led by node is an int8, whose comparisons and shifts take into account its sign
+func (emitter *Emitter) isSigned(node *ast.Node) bool {
+	datatype, ok := emitter.dataTypeOf(node)
+	return ok && symboltable.IsInt8(datatype)
+}
+
+//dataTypeOf returns the data type of the expression led by node, and false if it can't be obtained
+func (emitter *Emitter) dataTypeOf(node *ast.Node) (interface{}, bool) {
+	emitter.datatypeFactory.SetCxtNode(node)
+	emitter.datatypeFactory.SetScope(emitter.scope)
+	datatype, err := emitter.datatypeFactory.GetDataType()
+	return datatype, err == nil
+}
+
+//balanceOperands widens the operand saved in a register when the other one is saved in two, so both of them
+//can be operated as words. Returns the indexes of the registers of both operands and an error if needed
+func (emitter *Emitter) balanceOperands(functionCtx *FunctionCtx, leftOperandRegIndex *ResultRegIndex, rightOperandRegIndex *ResultRegIndex) (*ResultRegIndex, *ResultRegIndex, error) {
+	var err error
+	if leftOperandRegIndex.isPointer && !rightOperandRegIndex.isPointer {
+		rightOperandRegIndex, err = emitter.widen(functionCtx, rightOperandRegIndex)
+	} else if !leftOperandRegIndex.isPointer && rightOperandRegIndex.isPointer {
+		leftOperandRegIndex, err = emitter.widen(functionCtx, leftOperandRegIndex)
+	}
+	return leftOperandRegIndex, rightOperandRegIndex, err
+}
+
 //solveOperands save the operands of a operation in registers. It return the indexes of registers in which each operand
 //was stored and an error if needed
 func (emitter *Emitter) solveOperands(functionCtx *FunctionCtx) (*ResultRegIndex, *ResultRegIndex, error) {
@@ -2069,7 +3605,7 @@ func (emitter *Emitter) solveOperands(functionCtx *FunctionCtx) (*ResultRegIndex
 	}
 
 	emitter.ctxNode = leftOperand
-	leftOperandRegIndex, err := emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
+	leftOperandRegIndex, err := emitter.translateOperand(functionCtx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -2079,6 +3615,26 @@ func (emitter *Emitter) solveOperands(functionCtx *FunctionCtx) (*ResultRegIndex
 
 }
 
+//translateOperand translates the left operand of an operation. If it is the variable of a compound assignation, its
+//value is loaded from the address computed beforehand. Returns the indexes of the registers of the operand and an error
+//if needed
+func (emitter *Emitter) translateOperand(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	if emitter.ctxNode != functionCtx.loaded {
+		return emitter.translateOperation[emitter.ctxNode.Value.Type](functionCtx)
+	}
+	functionCtx.loaded = nil
+	address := functionCtx.loadedAddress
+	err := emitter.saveOpcode(I9XY1(address.highBitsIndex, address.lowBitsIndex)) //I = address
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.saveOpcode(IFX65(byte(functionCtx.loadedSize - 1)))
+	if err != nil {
+		return nil, err
+	}
+	return emitter.allocAndCopyPaste(functionCtx, functionCtx.loadedSize, 0, 1)
+}
+
 //index save in registers the value of a dereference.
 //Returns the indexes of registers in which it was stored the dereference and an error if needed
 func (emitter *Emitter) index(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
@@ -2096,10 +3652,16 @@ func (emitter *Emitter) asterisk(functionCtx *FunctionCtx) (*ResultRegIndex, err
 
 }
 
-//saveDereferenceInRegisters save in registers  the value of a dereference.
+//field save in registers the value of the field of a struct
+//it returns the indexes of registers in which the value was stored and an error if needed
+func (emitter *Emitter) field(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	return emitter.saveDereferenceInRegisters(functionCtx)
+}
+
+//saveDereferenceInRegisters save in registers  the value of a dereference or a field.
 //Returns the indexes of the registers in which it was stored and an error
 func (emitter *Emitter) saveDereferenceInRegisters(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
-	size, err := emitter.saveDereferenceAddressInI(functionCtx)
+	size, err := emitter.saveVariableAddressInI(functionCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -2179,6 +3741,31 @@ func (emitter *Emitter) allocAndCopyPaste(functionCtx *FunctionCtx, size int, x
 	return regIndex, nil
 }
 
+//sprite saves the rows of a sprite literal between the opcodes and saves their address in two registers.
+//Returns the indexes of the registers and an error if needed
+func (emitter *Emitter) sprite(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
+	regIndex, ok := functionCtx.registerHandler.AllocPointer()
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		err := errors.New(errorhandler.TooManyRegisters(line))
+		return nil, err
+	}
+	values := constant.SpriteValues(emitter.ctxNode)
+	address, err := emitter.saveData(values)
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.saveOpcode(I6XKK(regIndex.highBitsIndex, byte(address>>8)))
+	if err != nil {
+		return nil, err
+	}
+	err = emitter.saveOpcode(I6XKK(regIndex.lowBitsIndex, byte(address)))
+	if err != nil {
+		return nil, err
+	}
+	return regIndex, nil
+}
+
 //address save the address of its children in two registers, return the indexes of registers in which
 //it stores it and an error if needed
 func (emitter *Emitter) address(functionCtx *FunctionCtx) (*ResultRegIndex, error) {
@@ -2191,28 +3778,12 @@ func (emitter *Emitter) address(functionCtx *FunctionCtx) (*ResultRegIndex, erro
 	}
 
 	//we save the address in I
-	if emitter.ctxNode.Value.Type == token.IDENT {
-		ident := emitter.ctxNode.Value.Literal
-		_, isGlobalReference := emitter.globalVariables[ident]
-		if isGlobalReference {
-			_, err := emitter.saveGlobalReferenceAddressInI(0, 1)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			_, err := emitter.saveStackReferenceAddressInI(0, functionCtx)
-			if err != nil {
-				return nil, err
-			}
-		}
-	} else {
-		_, err := emitter.saveDereferenceAddressInI(functionCtx)
-		if err != nil {
-			return nil, err
-		}
+	_, err := emitter.saveVariableAddressInI(functionCtx)
+	if err != nil {
+		return nil, err
 	}
 	//then we save i in the registers
-	err := emitter.saveOpcode(I9XY2(regIndex.highBitsIndex, regIndex.lowBitsIndex))
+	err = emitter.saveOpcode(I9XY2(regIndex.highBitsIndex, regIndex.lowBitsIndex))
 	if err != nil {
 		return nil, err
 	}
@@ -2226,7 +3797,7 @@ func (emitter *Emitter) saveGlobalReferenceAddressInI(x byte, y byte) (int, erro
 	address := emitter.globalVariables[ident]
 	size := symboltable.GetSize(emitter.scope.Symbols[ident].DataType)
 
-	err := emitter.saveOpcode(I6XKK(x, byte(address<<8)))
+	err := emitter.saveOpcode(I6XKK(x, byte(address>>8)))
 	if err != nil {
 		return 0, err
 	}
@@ -2247,33 +3818,82 @@ func (emitter *Emitter) saveGlobalReferenceAddressInI(x byte, y byte) (int, erro
 //saveDereferenceAddressInI save the address of a dereference in I using the registers 0 and 1.
 //Returns the size of the reference it points to and an error
 func (emitter *Emitter) saveDereferenceAddressInI(functionCtx *FunctionCtx) (int, error) {
+	datatype, err := emitter.savePathAddressInI(functionCtx)
+	if err != nil {
+		return 0, err
+	}
+	return symboltable.GetSize(datatype), nil
+}
+
+//saveFieldAddressInI save the address of the field of a struct in I using the registers 0 and 1. The address of the struct
+//is saved first, dereferencing it if it is a pointer, and then the offset of the field is added.
+//Returns the data type of the field and an error
+func (emitter *Emitter) saveFieldAddressInI(functionCtx *FunctionCtx) (interface{}, error) {
+	const STRUCT = 0
+	const FIELD = 1
 	backup := emitter.ctxNode
-	//we save the address of the leaf in I:
-	leaf := GetLeafByRight(emitter.ctxNode)
-	emitter.ctxNode = leaf
-	leafIdent := emitter.ctxNode.Value.Literal
-	_, isInStack := functionCtx.stack.References[leafIdent]
-	if !isInStack {
-		_, isInGlobalMemory := emitter.globalVariables[leafIdent]
-		if !isInGlobalMemory {
-			return 0, errors.New(errorhandler.UnexpectedCompilerError())
-		}
-		_, err := emitter.saveGlobalReferenceAddressInI(0, 1)
+	identifier := emitter.ctxNode.Children[FIELD].Value.Literal
+	emitter.ctxNode = emitter.ctxNode.Children[STRUCT]
+	var datatype interface{}
+	var err error
+	if emitter.ctxNode.Value.Type == token.DOT {
+		datatype, err = emitter.saveFieldAddressInI(functionCtx)
+	} else {
+		datatype, err = emitter.savePathAddressInI(functionCtx)
+	}
+	emitter.ctxNode = backup
+	if err != nil {
+		return nil, err
+	}
+
+	pointer, isAPointer := datatype.(symboltable.Pointer)
+	//if the pointer was returned by a call, I already saves the address of the struct
+	isReturned := backup.Children[STRUCT].Value.Type == token.RPAREN
+	if isAPointer && !isReturned {
+		//we set V0 and V1 = the address the pointer saves, and then I = that address
+		err = emitter.saveOpcode(IFX65(1))
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
-	} else {
-		_, err := emitter.saveStackReferenceAddressInI(0, functionCtx)
+		err = emitter.saveOpcode(I9XY1(0, 1))
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 	}
-	symbol, ok := emitter.scope.Symbols[leafIdent]
+	if isAPointer {
+		datatype = pointer.PointsTo
+	}
 
+	field, ok := datatype.(symboltable.Struct).Field(identifier)
 	if !ok {
-		return 0, errors.New(errorhandler.UnexpectedCompilerError())
+		return nil, errors.New(errorhandler.UnexpectedCompilerError())
+	}
+	aux, ok := functionCtx.registerHandler.AllocSimple()
+	if !ok {
+		line := emitter.ctxNode.Value.Line
+		err := errors.New(errorhandler.TooManyRegisters(line))
+		return nil, err
+	}
+	err = emitter.saveFX1ESafely(aux.lowBitsIndex, field.Offset)
+	if err != nil {
+		return nil, err
+	}
+	functionCtx.registerHandler.Free(aux)
+
+	return field.DataType, nil
+}
+
+//savePathAddressInI save in I the address a sequence of dereferences and indexes leads to, using the registers 0 and 1.
+//Returns the data type of the reference it points to and an error
+func (emitter *Emitter) savePathAddressInI(functionCtx *FunctionCtx) (interface{}, error) {
+	backup := emitter.ctxNode
+	//we save the address of the leaf in I:
+	leaf := GetPathBase(emitter.ctxNode)
+	emitter.ctxNode = leaf
+	datatype, err := emitter.savePathBaseAddressInI(functionCtx)
+	if err != nil {
+		return nil, err
 	}
-	datatype := symbol.DataType
 
 	emitter.ctxNode = backup
 
@@ -2282,20 +3902,20 @@ func (emitter *Emitter) saveDereferenceAddressInI(functionCtx *FunctionCtx) (int
 		switch emitter.ctxNode.Value.Type {
 		//if we are analyzing a ], we add the index of the array to I to set the address of the next referenced element in I
 		case token.RBRACKET:
-			index, err := strconv.Atoi(emitter.ctxNode.Children[0].Value.Literal)
-			if err != nil {
-				return 0, errors.New(errorhandler.UnexpectedCompilerError())
+			index, ok := constant.Evaluate(emitter.ctxNode.Children[0], emitter.scope)
+			if !ok {
+				return nil, errors.New(errorhandler.UnexpectedCompilerError())
 			}
 			aux, ok := functionCtx.registerHandler.AllocSimple()
 			if !ok {
 				line := emitter.ctxNode.Value.Line
 				err := errors.New(errorhandler.TooManyRegisters(line))
-				return 0, err
+				return nil, err
 
 			}
-			err = emitter.saveFX1ESafely(aux.lowBitsIndex, index*symboltable.GetSize(datatype.(symboltable.Array).Of))
+			err := emitter.saveFX1ESafely(aux.lowBitsIndex, index*symboltable.GetSize(datatype.(symboltable.Array).Of))
 			if err != nil {
-				return 0, err
+				return nil, err
 			}
 			functionCtx.registerHandler.Free(aux)
 
@@ -2304,25 +3924,73 @@ func (emitter *Emitter) saveDereferenceAddressInI(functionCtx *FunctionCtx) (int
 
 		//if we are analyzing a *, then its value is the address  of the next referenced element, si we set I = value.
 		case token.ASTERISK:
-			//we set V0 and V1 = value saved from I in memory
-			err := emitter.saveOpcode(IFX65(1))
-			if err != nil {
-				return 0, err
-			}
-			//we set I=value founded previously in I
+			next := emitter.ctxNode.Children[0]
+			//if the pointer was returned by a call, I already saves its value
+			if next != leaf || leaf.Value.Type != token.RPAREN {
+				//we set V0 and V1 = value saved from I in memory
+				err := emitter.saveOpcode(IFX65(1))
+				if err != nil {
+					return nil, err
+				}
+				//we set I=value founded previously in I
 
-			err = emitter.saveOpcode(I9XY1(0, 1))
-			if err != nil {
-				return 0, err
+				err = emitter.saveOpcode(I9XY1(0, 1))
+				if err != nil {
+					return nil, err
+				}
 			}
 			datatype = datatype.(symboltable.Pointer).PointsTo
-			emitter.ctxNode = emitter.ctxNode.Children[0]
+			emitter.ctxNode = next
 
 		}
 
 	}
 
-	return symboltable.GetSize(datatype), nil
+	return datatype, nil
+}
+
+//savePathBaseAddressInI save in I the address of the variable a sequence of dereferences and indexes starts from,
+//using the registers 0 and 1. If it starts from a call, the value of the pointer the call returns is saved in I instead.
+//Returns the data type of the variable, or the return data type of the function, and an error
+func (emitter *Emitter) savePathBaseAddressInI(functionCtx *FunctionCtx) (interface{}, error) {
+	if emitter.ctxNode.Value.Type == token.RPAREN {
+		datatype, ok := emitter.dataTypeOf(emitter.ctxNode)
+		if !ok {
+			return nil, errors.New(errorhandler.UnexpectedCompilerError())
+		}
+		regIndex, err := emitter.call(functionCtx)
+		if err != nil {
+			return nil, err
+		}
+		err = emitter.saveOpcode(I9XY1(regIndex.highBitsIndex, regIndex.lowBitsIndex))
+		if err != nil {
+			return nil, err
+		}
+		functionCtx.registerHandler.Free(regIndex)
+		return datatype, nil
+	}
+	ident := emitter.ctxNode.Value.Literal
+	symbol, ok := emitter.scope.Symbols[ident]
+	if !ok {
+		return nil, errors.New(errorhandler.UnexpectedCompilerError())
+	}
+	_, isInStack := functionCtx.stack.References[ident]
+	if !isInStack {
+		_, isInGlobalMemory := emitter.globalVariables[ident]
+		if !isInGlobalMemory {
+			return nil, errors.New(errorhandler.UnexpectedCompilerError())
+		}
+		_, err := emitter.saveGlobalReferenceAddressInI(0, 1)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err := emitter.saveStackReferenceAddressInI(0, functionCtx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return symbol.DataType, nil
 }
 
 //saveStackReferenceAddressInI save the address of a reference saved in the stack in I using the register x
@@ -2341,8 +4009,22 @@ func (emitter *Emitter) saveStackReferenceAddressInI(x byte, functionCtx *Functi
 	if err != nil {
 		return 0, err
 	}
-
-	return size, emitter.saveFX1ESafely(x, reference.positionInStack)
+	err = emitter.saveFX1ESafely(x, reference.positionInStack)
+	if err != nil {
+		return 0, err
+	}
+	if reference.isIndirect {
+		//the stack saves the address of the variable, so we set V0 and V1 = that address and then I = V0 V1
+		err = emitter.saveOpcode(IFX65(1))
+		if err != nil {
+			return 0, err
+		}
+		err = emitter.saveOpcode(I9XY1(0, 1))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return size, nil
 
 }
 
@@ -2378,6 +4060,22 @@ func (emitter *Emitter) saveFX1ESafely(x byte, vx int) error {
 
 }
 
+//GetPathBase walks a sequence of dereferences and indexes led by head and returns the node it starts from,
+//which is an identifier or the call of a function that returns a pointer
+func GetPathBase(head *ast.Node) *ast.Node {
+	current := head
+	for {
+		switch {
+		case current.Value.Type == token.ASTERISK && len(current.Children) == 1:
+			current = current.Children[0]
+		case current.Value.Type == token.RBRACKET:
+			current = current.Children[1]
+		default:
+			return current
+		}
+	}
+}
+
 // GetLeafByRight gets the leaf by walking a tree using the right child of each node.
 func GetLeafByRight(head *ast.Node) *ast.Node {
 	current := head
@@ -2406,7 +4104,7 @@ func (emitter *Emitter) saveOpcode(opcode Opcode) error {
 func obtainSizeParams(params []interface{}) []int {
 	paramSizes := make([]int, 0)
 	for _, param := range params {
-		paramSizes = append(paramSizes, symboltable.GetSize(param))
+		paramSizes = append(paramSizes, symboltable.GetParamSize(param))
 	}
 	return paramSizes
 }
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -18,15 +19,18 @@ func TestStart(t *testing.T) {
 		description string
 		testPathTxt string
 		testPathRom string
+		testPathOut string
 		err         error
 	}
-	const numberOfValidTests = 41
+	const numberOfValidTests = 65
 	testCases := make([]cases, 0)
 	for i := 0; i < numberOfValidTests; i++ {
 		pathTxt := "../fixtures/emitter/c8-lang/test" + strconv.Itoa(i+1) + ".txt"
 		absPathTxt, err := filepath.Abs(pathTxt)
 		pathRom := "../fixtures/emitter/roms/test" + strconv.Itoa(i+1) + ".ch8"
 		absPathRom, err := filepath.Abs(pathRom)
+		pathOut := "../fixtures/emitter/outputs/test" + strconv.Itoa(i+1) + ".txt"
+		absPathOut, err := filepath.Abs(pathOut)
 
 		if err != nil {
 			assert.Error(t, err)
@@ -35,6 +39,7 @@ func TestStart(t *testing.T) {
 			description: "test" + strconv.Itoa(i+1),
 			testPathTxt: absPathTxt,
 			testPathRom: absPathRom,
+			testPathOut: absPathOut,
 			err:         nil,
 		})
 
@@ -52,6 +57,7 @@ func TestStart(t *testing.T) {
 		valid := program.Build(&tokens, tree)
 		assert.True(t, valid, "invalid syntax")
 		semantic := semanticAnalyzer.NewSemanticAnalyzer(tree)
+		semantic.SetSourceDir(filepath.Dir(scenario.testPathTxt))
 		scope, err := semantic.Start()
 		assert.NoError(t, err)
 		emitter := NewEmitter(tree, scope)
@@ -59,7 +65,12 @@ func TestStart(t *testing.T) {
 		assert.NoError(t, err)
 		rom, err := os.ReadFile(scenario.testPathRom)
 		assert.NoError(t, err)
-		assert.NotEqual(t, t, machineCode, rom)
+		assert.Equal(t, rom, machineCode, scenario.description)
+		//the events of the program are the draws it does, one per line
+		out, err := os.ReadFile(scenario.testPathOut)
+		assert.NoError(t, err)
+		events := NewInterpreter(machineCode).Run()
+		assert.Equal(t, string(out), strings.Join(events, "\n")+"\n", scenario.description)
 	}
 
 }
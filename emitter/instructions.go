@@ -101,6 +101,14 @@ func IANNN(nnn uint16) Opcode {
 	return iannn
 }
 
+//IBNNN writes in an Opcode the chip 8 instruction BNNN which jumps to location nnn + V0
+func IBNNN(nnn uint16) Opcode {
+	var ibnnn Opcode
+	ibnnn[0] = 0xB0 | byte(nnn>>8)
+	ibnnn[1] = byte(nnn)
+	return ibnnn
+}
+
 //IFX55 writes in an Opcode the chip 8 instruction FX55 which stores registers V0 through Vx in memory starting at location I.
 func IFX55(x byte) Opcode {
 	var ifx55 Opcode
@@ -159,6 +167,14 @@ func I4XKK(x byte, kk byte) Opcode {
 	return i4xkk
 }
 
+//I5XY0 writes in an Opcode the chip 8 instruction 5XY0 which skip the next instruction if vx = vy.
+func I5XY0(x byte, y byte) Opcode {
+	var i5xy0 Opcode
+	i5xy0[0] = 0x50 | x
+	i5xy0[1] = y << 4
+	return i5xy0
+}
+
 //I2NNN writes in an Opcode the chip 8 instruction I2NNN CALL (ADDR)
 func I2NNN(nnn uint16) Opcode {
 	var i2nnn Opcode
@@ -0,0 +1,237 @@
+package emitter
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+const (
+	MaxCycles    = 300000       //The amount of instructions a program can run before the interpreter stops it
+	MaxEvents    = 40           //The amount of draws a program can do before the interpreter stops it
+	PressedKey   = 1            //The only key the interpreter reports as pressed, and the one waitKey() receives
+	MainReturn   = RomStart + 6 //The address the program reaches once main returns
+	FontEnd      = RomStart     //The sprites of the font are below this address
+	EventDone    = "DONE"       //The last event when main returns
+	EventStopped = "STOPPED"    //The last event when the program runs out of cycles or draws
+)
+
+//Interpreter runs the machine code of a program in a chip 8 machine that supports the instructions 9XY1 and 9XY2,
+//and records each draw as an event, so that a test can check what the program does and not only the opcodes it has
+type Interpreter struct {
+	memory [Memory]byte
+	v      [16]byte
+	i      uint16
+	pc     uint16
+	stack  []uint16
+	dt     byte
+	random *rand.Rand
+	events []string
+}
+
+func NewInterpreter(machineCode []byte) *Interpreter {
+	interpreter := &Interpreter{
+		pc:     RomStart,
+		stack:  make([]uint16, 0),
+		random: rand.New(rand.NewSource(1)),
+		events: make([]string, 0),
+	}
+	copy(interpreter.memory[RomStart:], machineCode)
+	return interpreter
+}
+
+//Run executes the program until main returns or it runs out of cycles or draws, and returns its events
+func (interpreter *Interpreter) Run() []string {
+	for cycle := 0; cycle < MaxCycles && len(interpreter.events) < MaxEvents; cycle++ {
+		if interpreter.pc == MainReturn {
+			return append(interpreter.events, EventDone)
+		}
+		err := interpreter.step()
+		if err != nil {
+			return append(interpreter.events, err.Error())
+		}
+	}
+	return append(interpreter.events, EventStopped)
+}
+
+//step executes the instruction pointed by the program counter. Returns an error if the instruction is unknown
+func (interpreter *Interpreter) step() error {
+	pc := interpreter.pc
+	opcode := uint16(interpreter.memory[pc])<<8 | uint16(interpreter.memory[pc+1])
+	interpreter.pc += 2
+	v := &interpreter.v
+	x := byte(opcode>>8) & 0xF
+	y := byte(opcode>>4) & 0xF
+	kk := byte(opcode)
+	nnn := opcode & 0xFFF
+	switch opcode >> 12 {
+	case 0x0:
+		switch opcode {
+		case 0x00E0:
+		case 0x00EE:
+			if len(interpreter.stack) == 0 {
+				return fmt.Errorf("RETURN WITHOUT CALL AT %03X", pc)
+			}
+			interpreter.pc = interpreter.stack[len(interpreter.stack)-1]
+			interpreter.stack = interpreter.stack[:len(interpreter.stack)-1]
+		default:
+			return unknown(opcode, pc)
+		}
+	case 0x1:
+		interpreter.pc = nnn
+	case 0x2:
+		interpreter.stack = append(interpreter.stack, interpreter.pc)
+		interpreter.pc = nnn
+	case 0x3:
+		if v[x] == kk {
+			interpreter.pc += 2
+		}
+	case 0x4:
+		if v[x] != kk {
+			interpreter.pc += 2
+		}
+	case 0x5:
+		if v[x] == v[y] {
+			interpreter.pc += 2
+		}
+	case 0x6:
+		v[x] = kk
+	case 0x7:
+		v[x] += kk
+	case 0x8:
+		return interpreter.arithmetic(opcode, x, y)
+	case 0x9:
+		switch opcode & 0xF {
+		case 0x0:
+			if v[x] != v[y] {
+				interpreter.pc += 2
+			}
+		case 0x1:
+			interpreter.i = uint16(v[x])<<8 | uint16(v[y])
+		case 0x2:
+			v[x] = byte(interpreter.i >> 8)
+			v[y] = byte(interpreter.i)
+		default:
+			return unknown(opcode, pc)
+		}
+	case 0xA:
+		interpreter.i = nnn
+	case 0xB:
+		interpreter.pc = nnn + uint16(v[0])
+	case 0xC:
+		v[x] = byte(interpreter.random.Intn(256)) & kk
+	case 0xD:
+		interpreter.draw(x, y, opcode&0xF)
+	case 0xE:
+		switch kk {
+		case 0x9E:
+			if v[x] == PressedKey {
+				interpreter.pc += 2
+			}
+		case 0xA1:
+			if v[x] != PressedKey {
+				interpreter.pc += 2
+			}
+		default:
+			return unknown(opcode, pc)
+		}
+	case 0xF:
+		return interpreter.misc(opcode, x, kk)
+	}
+	return nil
+}
+
+//arithmetic executes the instructions 8XYN, which set VF as the carry, the borrow or the bit shifted out
+func (interpreter *Interpreter) arithmetic(opcode uint16, x byte, y byte) error {
+	v := &interpreter.v
+	flag := byte(0)
+	switch opcode & 0xF {
+	case 0x0:
+		v[x] = v[y]
+		return nil
+	case 0x1:
+		v[x] |= v[y]
+		return nil
+	case 0x2:
+		v[x] &= v[y]
+		return nil
+	case 0x3:
+		v[x] ^= v[y]
+		return nil
+	case 0x4:
+		sum := uint16(v[x]) + uint16(v[y])
+		v[x] = byte(sum)
+		flag = byte(sum >> 8)
+	case 0x5:
+		if v[x] > v[y] {
+			flag = 1
+		}
+		v[x] -= v[y]
+	case 0x6:
+		flag = v[x] & 1
+		v[x] >>= 1
+	case 0x7:
+		if v[y] > v[x] {
+			flag = 1
+		}
+		v[x] = v[y] - v[x]
+	case 0xE:
+		flag = v[x] >> 7
+		v[x] <<= 1
+	default:
+		return unknown(opcode, interpreter.pc-2)
+	}
+	v[Carry] = flag
+	return nil
+}
+
+//misc executes the instructions FXKK
+func (interpreter *Interpreter) misc(opcode uint16, x byte, kk byte) error {
+	v := &interpreter.v
+	switch kk {
+	case 0x07:
+		v[x] = interpreter.dt
+	case 0x0A:
+		v[x] = PressedKey
+	case 0x15:
+		interpreter.dt = v[x]
+	case 0x18:
+	case 0x1E:
+		interpreter.i += uint16(v[x])
+	case 0x29:
+		interpreter.i = uint16(v[x]) * 5
+	case 0x33:
+		interpreter.memory[interpreter.i] = v[x] / 100
+		interpreter.memory[interpreter.i+1] = v[x] / 10 % 10
+		interpreter.memory[interpreter.i+2] = v[x] % 10
+	case 0x55:
+		for r := uint16(0); r <= uint16(x); r++ {
+			interpreter.memory[interpreter.i+r] = v[r]
+		}
+	case 0x65:
+		for r := uint16(0); r <= uint16(x); r++ {
+			v[r] = interpreter.memory[interpreter.i+r]
+		}
+	default:
+		return unknown(opcode, interpreter.pc-2)
+	}
+	return nil
+}
+
+//draw records the draw of a sprite of n rows. A sprite of the font is recorded as the value it represents,
+//any other as its rows. Nothing collides, so VF is always 0
+func (interpreter *Interpreter) draw(x byte, y byte, n uint16) {
+	vx := interpreter.v[x]
+	vy := interpreter.v[y]
+	if interpreter.i < FontEnd {
+		interpreter.events = append(interpreter.events, fmt.Sprintf("FONT x=%d y=%d val=%d", vx, vy, interpreter.i/5))
+	} else {
+		rows := interpreter.memory[interpreter.i : interpreter.i+n]
+		interpreter.events = append(interpreter.events, fmt.Sprintf("DRAW x=%d y=%d sprite=%v", vx, vy, rows))
+	}
+	interpreter.v[Carry] = 0
+}
+
+//unknown returns the error of an instruction the interpreter doesn't know
+func unknown(opcode uint16, address uint16) error {
+	return fmt.Errorf("UNKNOWN %04X AT %03X", opcode, address)
+}
@@ -5,11 +5,11 @@ import "github.com/NoetherianRing/c8-compiler/symboltable"
 const NonAvailable = -1
 
 //ResultRegIndex stores the indexes of the registers in which a result is stored, and a field that says if the result
-//stored in the registers is a pointer (or a simple if false)
+//is stored in two registers, as pointers and words are (or in one if false)
 type ResultRegIndex struct {
-	highBitsIndex byte //if the result is a pointer, highBitsIndex is the index of the register that stores the first 8 bits.
-	lowBitsIndex  byte //if the result is a pointer, lowBitsIndex is the index of the register  that stores the last 8 bits.
-	//if the result is a simple, lowBitsIndex is the index of the register in which the entire result is stored.
+	highBitsIndex byte //if the result is a pointer or a word, highBitsIndex is the index of the register that stores the first 8 bits.
+	lowBitsIndex  byte //if the result is a pointer or a word, lowBitsIndex is the index of the register  that stores the last 8 bits.
+	//if the result is a simple of one byte, lowBitsIndex is the index of the register in which the entire result is stored.
 	isPointer bool
 }
 
@@ -34,9 +34,15 @@ func NewRegisterHandler() *RegisterHandler {
 func (handler *RegisterHandler) Alloc(datatype interface{}) (*ResultRegIndex, bool) {
 	switch datatype.(type) {
 	case symboltable.Simple:
+		//words are saved in two registers, like pointers
+		if symboltable.GetSize(datatype) == 2 {
+			return handler.AllocPointer()
+		}
 		return handler.AllocSimple()
 	case symboltable.Pointer:
 		return handler.AllocPointer()
+	case symboltable.Enum:
+		return handler.AllocSimple()
 	default:
 		return nil, false
 	}
@@ -11,4 +11,11 @@ const (
 	True                       = 1
 	False                      = 0
 	SizePointer                = 2
+	SizeCallBackup             = AmountOfRegistersToOperate + 2 //The registers backup of a call followed by its return value, of up to two bytes
+	SizeMoveStackPointer       = 5 * 2                          //The amount of bytes of the opcodes that move the stack pointer
+	MinCasesJumpTable          = 4                              //The minimum amount of values a switch needs to use a jump table
+	MaxJumpTable               = 128                            //The maximum amount of jumps in a jump table, so that the offset of the last one fits in V0
+	SizeSpill                  = RegisterStackAddress2 + 1      //The registers saved around a block copy or comparison, from v0 to the stack pointer
+	SizeBlock                  = 16                             //The amount of bytes a block copy moves with each FX65 and FX55, using every register
+	SizeCompareBlock           = SizeBlock / 2                  //The amount of bytes of each array a block comparison loads, half of the registers for each one
 )
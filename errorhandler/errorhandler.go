@@ -29,6 +29,21 @@ func ByteOutOfRange(line int, number int) string {
 
 }
 
+func WordOutOfRange(line int, number int) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nNumber: " + strconv.Itoa(number) + " is greater than a word"
+	return errorString
+}
+
+func InvalidConversion(line int, from string, to string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nInvalid conversion: " + from + " can't be converted to " + to +
+		"\nThe allowed conversions are: between byte, word and int8, from bool to byte, word or int8 (true is 1)," +
+		" from byte, word or int8 to bool (any value but 0 is true), between pointers, from pointers to byte or word" +
+		", from byte or word to pointers, and between enums and byte, word or int8"
+	return errorString
+}
+
 func InvalidAssignation(line int, datatype string) string {
 	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
 		"\nInvalid assignation to: " + datatype
@@ -112,6 +127,53 @@ func UnreachableCode(line int) string {
 	return errorString
 
 }
+func MissingReturn(line int, function string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nMissing return in function " + function
+	return errorString
+
+}
+func LoopControlOutsideLoop(line int, keyword string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\n" + keyword + " outside a loop"
+	return errorString
+
+}
+func UnresolvedLabel(line int, label string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThere is no enclosing loop labeled " + label
+	return errorString
+
+}
+func InvalidInitialization(line int, datatype string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nA variable can't be initialized with a value of type " + datatype
+	return errorString
+
+}
+func NotConstantInitialization(line int) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nGlobal variables and array literals can only hold values known at compile time"
+	return errorString
+
+}
+func DivisionByZero(line int) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nDivision by zero"
+	return errorString
+
+}
+func ConstantOverflow(line int, datatype string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThe value of the expression doesn't fit in a value of type " + datatype
+	return errorString
+
+}
+func InvalidLiteral(line int, column int, literal string) string {
+	errorString := "\n invalid literal: " + literal + " \n in line: " + strconv.Itoa(line) + ", column: " + strconv.Itoa(column)
+	return errorString
+}
+
 func IllegalToken(line int, t string) string {
 
 	errorString := "\n illegal token: \"" + t + "\" \n in line: " + strconv.Itoa(line)
@@ -131,6 +193,165 @@ func MainFunctionNeeded() string {
 	errorString := "main function needed\n"
 	return errorString
 }
+func NotConstantValue(line int, constant string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThe value of the constant " + constant + " can't be known at compile time"
+	return errorString
+
+}
+
+func InvalidConstant(line int, datatype string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nA constant can't be of type " + datatype
+	return errorString
+
+}
+
+func NotConstantLength(line int) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThe length of an array must be a byte literal or a constant"
+	return errorString
+
+}
+
+func NotConstantIndex(line int) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThe index of an array must be a byte literal or a constant"
+	return errorString
+
+}
+
+func ConstantModification(line int, constant string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\n" + constant + " is a constant, it can't be assigned or addressed"
+	return errorString
+
+}
+
+func InvalidSpriteRow(line int, row string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThe sprite row \"" + row + "\" must have up to 8 pixels, written as # or ."
+	return errorString
+
+}
+
+func InvalidSpriteHeight(line int, height int) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nA sprite can have up to 15 rows, but it has " + strconv.Itoa(height)
+	return errorString
+
+}
+
+func EmbedOutsideGlobalScope(line int) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nFiles can only be embedded in global variables"
+	return errorString
+
+}
+
+func InvalidEmbed(line int, path string, reason string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThe file " + path + " can't be embedded: " + reason
+	return errorString
+
+}
+
+func EmbedSizeMismatch(line int, path string, size int, datatype string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThe file " + path + " has " + strconv.Itoa(size) + " bytes, but it's embedded in a variable of type " + datatype
+	return errorString
+
+}
+
+func EmbedNeedsByteArray(line int, path string, datatype string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThe file " + path + " can only be embedded in an array of bytes, not in a variable of type " + datatype
+	return errorString
+
+}
+
+func NotConstantCase(line int) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThe values of a case must be known at compile time"
+	return errorString
+
+}
+
+func DuplicateCase(line int, value int) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThe value " + strconv.Itoa(value) + " is already handled by another case of the switch"
+	return errorString
+
+}
+
+func MultipleDefaults(line int) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nA switch can only have one default"
+	return errorString
+
+}
+
+func TypeOutsideGlobalScope(line int) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) + "\ntype declaration outside global scope"
+	return errorString
+}
+
+func NotAType(line int, reference string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\n" + reference + " is not a type"
+	return errorString
+
+}
+
+func StructContainsItself(line int, datatype string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\n" + datatype + " can't contain itself, only a pointer to itself"
+	return errorString
+
+}
+
+func TypeIsNotAValue(line int, reference string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\n" + reference + " is a type, it can't be used as a value"
+	return errorString
+
+}
+
+func UnresolvedField(line int, field string, datatype string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\n" + datatype + " has no field " + field
+	return errorString
+
+}
+
+func InvalidComparison(line int, datatype string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nValues of type " + datatype + " can't be compared"
+	return errorString
+
+}
+
+func LenOfNonArray(line int, datatype string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nlen can only be applied to arrays, not to values of type " + datatype
+	return errorString
+
+}
+
+func NotAnArrayVariable(line int, datatype string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nValues of type " + datatype + " can only be assigned, compared and passed as params from variables"
+	return errorString
+
+}
+
+func UndefinedOperator(line int, operator string, datatype string) string {
+	errorString := "semantic error\nin line: " + strconv.Itoa(line) +
+		"\nThe operator " + operator + " is not defined for values of type " + datatype
+	return errorString
+
+}
+
 func SyntaxError() string {
 	errorString := "syntactic error"
 	return errorString
@@ -5,13 +5,18 @@ import (
 	"github.com/NoetherianRing/c8-compiler/errorhandler"
 	"github.com/NoetherianRing/c8-compiler/token"
 	"os"
+	"strconv"
+	"strings"
 )
 
 type Lexer struct {
-	input string
-	index int    //current position
-	cChar string //current char
-	cLine int    //current line
+	input      string
+	index      int    //current position
+	cChar      string //current char
+	cLine      int    //current line
+	lineStart  int    //position of the first char of the current line
+	tokenStart int    //position of the first char of the last token read
+	err        error  //error found while reading the last token, if it's illegal
 }
 
 func NewLexer(filename string) (*Lexer, error) {
@@ -22,7 +27,7 @@ func NewLexer(filename string) (*Lexer, error) {
 		return nil, err
 	}
 
-	l := &Lexer{input: string(source), index: -1, cChar: "", cLine: 0}
+	l := &Lexer{input: string(source), index: -1, cChar: "", cLine: 0, lineStart: 0}
 	l.readChar()
 	return l, nil
 }
@@ -40,6 +45,9 @@ func (l *Lexer) GetTokens() ([]token.Token, error) {
 	t := l.nextToken()
 	for t.Type != token.EOF {
 		if t.Type == token.ILLEGAL {
+			if l.err != nil {
+				return nil, l.err
+			}
 			errorString := errorhandler.IllegalToken(t.Line, t.Literal)
 			return nil, errors.New(errorString)
 		}
@@ -54,6 +62,8 @@ func (l *Lexer) nextToken() token.Token {
 
 	l.skipWhitespace()
 	l.skipComment()
+	l.tokenStart = l.index
+	l.err = nil
 
 	switch l.cChar {
 	case token.EQ:
@@ -69,7 +79,7 @@ func (l *Lexer) nextToken() token.Token {
 			l.readChar()
 			tok = token.NewToken(token.LAND, token.LAND, l.cLine)
 		} else {
-			tok = token.NewToken(token.AND, token.AND, l.cLine)
+			tok = l.operatorOrAssignment(token.AND, token.ANDEQ)
 
 		}
 	case token.OR:
@@ -77,7 +87,7 @@ func (l *Lexer) nextToken() token.Token {
 			l.readChar()
 			tok = token.NewToken(token.LOR, token.LOR, l.cLine)
 		} else {
-			tok = token.NewToken(token.OR, token.OR, l.cLine)
+			tok = l.operatorOrAssignment(token.OR, token.OREQ)
 
 		}
 	case token.BANG:
@@ -88,15 +98,25 @@ func (l *Lexer) nextToken() token.Token {
 			tok = token.NewToken(token.BANG, token.BANG, l.cLine)
 		}
 	case token.PLUS:
-		tok = token.NewToken(token.PLUS, token.PLUS, l.cLine)
+		if l.peekChar() == token.PLUS {
+			l.readChar()
+			tok = token.NewToken(token.PLUSPLUS, token.PLUSPLUS, l.cLine)
+		} else {
+			tok = l.operatorOrAssignment(token.PLUS, token.PLUSEQ)
+		}
 	case token.ASTERISK:
-		tok = token.NewToken(token.ASTERISK, token.ASTERISK, l.cLine)
+		tok = l.operatorOrAssignment(token.ASTERISK, token.ASTERISKEQ)
 	case token.MINUS:
-		tok = token.NewToken(token.MINUS, token.MINUS, l.cLine)
+		if l.peekChar() == token.MINUS {
+			l.readChar()
+			tok = token.NewToken(token.MINUSMINUS, token.MINUSMINUS, l.cLine)
+		} else {
+			tok = l.operatorOrAssignment(token.MINUS, token.MINUSEQ)
+		}
 	case token.SLASH:
-		tok = token.NewToken(token.SLASH, token.SLASH, l.cLine)
+		tok = l.operatorOrAssignment(token.SLASH, token.SLASHEQ)
 	case token.PERCENT:
-		tok = token.NewToken(token.PERCENT, token.PERCENT, l.cLine)
+		tok = l.operatorOrAssignment(token.PERCENT, token.PERCENTEQ)
 	case token.DOLLAR:
 		tok = token.NewToken(token.DOLLAR, token.DOLLAR, l.cLine)
 	case token.GT:
@@ -107,7 +127,7 @@ func (l *Lexer) nextToken() token.Token {
 		} else {
 			if peek == token.GT {
 				l.readChar()
-				tok = token.NewToken(token.GTGT, token.GTGT, l.cLine)
+				tok = l.operatorOrAssignment(token.GTGT, token.GTGTEQ)
 
 			} else {
 				tok = token.NewToken(token.GT, token.GT, l.cLine)
@@ -116,6 +136,12 @@ func (l *Lexer) nextToken() token.Token {
 		}
 	case token.COMMA:
 		tok = token.NewToken(token.COMMA, token.COMMA, l.cLine)
+	case token.DOT:
+		tok = token.NewToken(token.DOT, token.DOT, l.cLine)
+	case token.COLON:
+		tok = token.NewToken(token.COLON, token.COLON, l.cLine)
+	case token.SEMICOLON:
+		tok = token.NewToken(token.SEMICOLON, token.SEMICOLON, l.cLine)
 	case token.LPAREN:
 		tok = token.NewToken(token.LPAREN, token.LPAREN, l.cLine)
 	case token.RPAREN:
@@ -136,7 +162,7 @@ func (l *Lexer) nextToken() token.Token {
 		} else {
 			if peek == token.LT {
 				l.readChar()
-				tok = token.NewToken(token.LTLT, token.LTLT, l.cLine)
+				tok = l.operatorOrAssignment(token.LTLT, token.LTLTEQ)
 
 			} else {
 				tok = token.NewToken(token.LT, token.LT, l.cLine)
@@ -144,23 +170,32 @@ func (l *Lexer) nextToken() token.Token {
 			}
 		}
 	case token.XOR:
-		tok = token.NewToken(token.XOR, token.XOR, l.cLine)
+		tok = l.operatorOrAssignment(token.XOR, token.XOREQ)
+	case token.QUESTION:
+		tok = token.NewToken(token.QUESTION, token.QUESTION, l.cLine)
+	case token.TILDE:
+		tok = token.NewToken(token.TILDE, token.TILDE, l.cLine)
 	case token.NEWLINE:
 		tok = token.NewToken(token.NEWLINE, token.NEWLINE, l.cLine)
 		l.cLine += 1
+		l.lineStart = l.index + 1
+	case "'":
+		return l.readCharacter()
+	case "\"":
+		return l.readString()
 	case "":
 		tok = token.NewToken(token.EOF, token.EOF, l.cLine)
 	default:
 		if isLetter(l.cChar) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
+			if tok.Literal == token.SPRITE && l.isSpriteLiteral() {
+				tok.Type = token.SPRITE
+			}
 			tok.Line = l.cLine
 			return tok
 		} else if isDigit(l.cChar) {
-			tok.Literal = l.readNumber()
-			tok.Type = token.BYTE
-			tok.Line = l.cLine
-			return tok
+			return l.readNumber()
 		} else {
 			tok = token.NewToken(token.ILLEGAL, l.cChar, l.cLine)
 		}
@@ -169,12 +204,111 @@ func (l *Lexer) nextToken() token.Token {
 	return tok
 
 }
-func (l *Lexer) readNumber() string {
+
+//operatorOrAssignment returns the token of a compound assignment, like +=, if the operator is followed by "=".
+//Otherwise, it returns the token of the operator
+func (l *Lexer) operatorOrAssignment(operator token.Type, assignment token.Type) token.Token {
+	if l.peekChar() == token.EQ {
+		l.readChar()
+		return token.NewToken(assignment, string(assignment), l.cLine)
+	}
+	return token.NewToken(operator, string(operator), l.cLine)
+}
+
+//readNumber reads a decimal, hexadecimal (0x) or binary (0b) literal, whose digits can be separated by "_",
+//and returns a byte token whose literal is the decimal representation of the number
+func (l *Lexer) readNumber() token.Token {
+	position := l.index
+	for isLetter(l.cChar) || isDigit(l.cChar) {
+		l.readChar()
+	}
+	literal := l.input[position:l.index]
+
+	base := 10
+	digits := literal
+	if len(literal) > 2 && literal[0] == '0' {
+		switch literal[1] {
+		case 'x', 'X':
+			base = 16
+			digits = literal[2:]
+		case 'b', 'B':
+			base = 2
+			digits = literal[2:]
+		}
+	}
+	//a separator must be between two digits, or between the prefix and a digit
+	for i := range digits {
+		if digits[i] == '_' && (i == len(digits)-1 || digits[i+1] == '_') {
+			return l.illegalLiteral(literal)
+		}
+	}
+	value, err := strconv.ParseUint(strings.ReplaceAll(digits, "_", ""), base, 64)
+	if err != nil {
+		return l.illegalLiteral(literal)
+	}
+	return token.NewToken(token.BYTE, strconv.FormatUint(value, 10), l.cLine)
+}
+
+//readCharacter reads a character literal, like 'A' or '\n', and returns a byte token whose literal is the
+//decimal representation of the character
+func (l *Lexer) readCharacter() token.Token {
+	position := l.index
+	l.readChar()
+	for l.cChar != "'" && l.cChar != "\n" && l.cChar != "" {
+		if l.cChar == "\\" {
+			l.readChar()
+		}
+		l.readChar()
+	}
+	if l.cChar == "'" {
+		l.readChar()
+	}
+	literal := l.input[position:l.index]
+
+	value, err := strconv.Unquote(literal)
+	if err != nil || len(value) != 1 {
+		return l.illegalLiteral(literal)
+	}
+	return token.NewToken(token.BYTE, strconv.Itoa(int(value[0])), l.cLine)
+}
+
+//readString reads a string literal, which can't have line breaks, and returns a string token whose literal is the
+//content of the string
+func (l *Lexer) readString() token.Token {
 	position := l.index
-	for isDigit(l.cChar) {
+	l.readChar()
+	for l.cChar != "\"" && l.cChar != "\n" && l.cChar != "" {
 		l.readChar()
 	}
-	return l.input[position:l.index]
+	if l.cChar != "\"" {
+		return l.illegalLiteral(l.input[position:l.index])
+	}
+	l.readChar()
+	return token.NewToken(token.STRING, l.input[position+1:l.index-1], l.cLine)
+}
+
+//isSpriteLiteral checks if the next chars are a brace followed by a string. "sprite" isn't a keyword, so it can
+//still be used as an identifier, and this is how we know that it starts a sprite literal
+func (l *Lexer) isSpriteLiteral() bool {
+	i := l.index
+	for i < len(l.input) && strings.ContainsRune(" \t\r", rune(l.input[i])) {
+		i++
+	}
+	if i >= len(l.input) || l.input[i] != '{' {
+		return false
+	}
+	i++
+	for i < len(l.input) && strings.ContainsRune(" \t\r\n", rune(l.input[i])) {
+		i++
+	}
+	return i < len(l.input) && l.input[i] == '"'
+}
+
+//illegalLiteral returns an illegal token and saves the error, which includes the column of the literal
+func (l *Lexer) illegalLiteral(literal string) token.Token {
+	column := l.tokenStart - l.lineStart + 1
+	l.err = errors.New(errorhandler.InvalidLiteral(l.cLine, column, literal))
+	return token.NewToken(token.ILLEGAL, literal, l.cLine)
 }
 func isDigit(ch string) bool {
 	return "0" <= ch && ch <= "9"
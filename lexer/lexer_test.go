@@ -40,7 +40,7 @@ func TestNextToken(t *testing.T) {
 				token.NewToken(token.LET, "let", 4),
 				token.NewToken(token.IDENT, "f_o0", 4),
 				token.NewToken(token.MINUS, token.MINUS, 4),
-				token.NewToken(token.ILLEGAL, ";", 4),
+				token.NewToken(token.SEMICOLON, ";", 4),
 				token.NewToken(token.NEWLINE, token.NEWLINE, 4),
 				token.NewToken(token.RBRACE, token.RBRACE, 5),
 				token.NewToken(token.EOF, token.EOF, 5),
@@ -140,6 +140,121 @@ func TestNextToken(t *testing.T) {
 				token.NewToken(token.EOF, token.EOF, 7),
 			},
 		},
+		{
+			description: "TestNextToken4",
+			fixture:     "../fixtures/TestNextToken4.txt",
+			expectedTokens: []token.Token{
+				token.NewToken(token.LET, "let", 0),
+				token.NewToken(token.IDENT, "mask", 0),
+				token.NewToken(token.TYPEBYTE, "byte", 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.BYTE, "240", 0),
+				token.NewToken(token.OR, token.OR, 0),
+				token.NewToken(token.BYTE, "15", 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.LET, "let", 1),
+				token.NewToken(token.IDENT, "big", 1),
+				token.NewToken(token.EQ, token.EQ, 1),
+				token.NewToken(token.BYTE, "1000", 1),
+				token.NewToken(token.PLUS, token.PLUS, 1),
+				token.NewToken(token.BYTE, "31", 1),
+				token.NewToken(token.PLUS, token.PLUS, 1),
+				token.NewToken(token.BYTE, "65", 1),
+				token.NewToken(token.PLUS, token.PLUS, 1),
+				token.NewToken(token.BYTE, "10", 1),
+				token.NewToken(token.PLUS, token.PLUS, 1),
+				token.NewToken(token.BYTE, "39", 1),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 1),
+				token.NewToken(token.IDENT, "sprite", 2),
+				token.NewToken(token.EQ, token.EQ, 2),
+				token.NewToken(token.SPRITE, "sprite", 2),
+				token.NewToken(token.LBRACE, token.LBRACE, 2),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 2),
+				token.NewToken(token.STRING, "#..#", 3),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 3),
+				token.NewToken(token.RBRACE, token.RBRACE, 4),
+				token.NewToken(token.EOF, token.EOF, 4),
+			},
+		},
+		{
+			description: "TestNextToken5",
+			fixture:     "../fixtures/TestNextToken5.txt",
+			expectedTokens: []token.Token{
+				token.NewToken(token.IDENT, "x", 0),
+				token.NewToken(token.PLUSEQ, token.PLUSEQ, 0),
+				token.NewToken(token.BYTE, "1", 0),
+				token.NewToken(token.MINUSEQ, token.MINUSEQ, 0),
+				token.NewToken(token.ASTERISKEQ, token.ASTERISKEQ, 0),
+				token.NewToken(token.SLASHEQ, token.SLASHEQ, 0),
+				token.NewToken(token.PERCENTEQ, token.PERCENTEQ, 0),
+				token.NewToken(token.ANDEQ, token.ANDEQ, 0),
+				token.NewToken(token.OREQ, token.OREQ, 0),
+				token.NewToken(token.XOREQ, token.XOREQ, 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.IDENT, "x", 1),
+				token.NewToken(token.LTLTEQ, token.LTLTEQ, 1),
+				token.NewToken(token.BYTE, "2", 1),
+				token.NewToken(token.GTGTEQ, token.GTGTEQ, 1),
+				token.NewToken(token.PLUSPLUS, token.PLUSPLUS, 1),
+				token.NewToken(token.MINUSMINUS, token.MINUSMINUS, 1),
+				token.NewToken(token.LAND, token.LAND, 1),
+				token.NewToken(token.LTLT, token.LTLT, 1),
+				token.NewToken(token.IDENT, "x", 1),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 1),
+				token.NewToken(token.TILDE, token.TILDE, 2),
+				token.NewToken(token.MINUS, token.MINUS, 2),
+				token.NewToken(token.IDENT, "x", 2),
+				token.NewToken(token.QUESTION, token.QUESTION, 2),
+				token.NewToken(token.BYTE, "1", 2),
+				token.NewToken(token.COLON, token.COLON, 2),
+				token.NewToken(token.BYTE, "2", 2),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 2),
+				token.NewToken(token.TYPE, "type", 3),
+				token.NewToken(token.IDENT, "E", 3),
+				token.NewToken(token.STRUCT, "struct", 3),
+				token.NewToken(token.LBRACE, token.LBRACE, 3),
+				token.NewToken(token.IDENT, "p", 3),
+				token.NewToken(token.DOT, token.DOT, 3),
+				token.NewToken(token.IDENT, "x", 3),
+				token.NewToken(token.RBRACE, token.RBRACE, 3),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 3),
+				token.NewToken(token.LET, "let", 4),
+				token.NewToken(token.IDENT, "w", 4),
+				token.NewToken(token.TYPEWORD, "word", 4),
+				token.NewToken(token.EQ, token.EQ, 4),
+				token.NewToken(token.BYTE, "300", 4),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 4),
+				token.NewToken(token.LET, "let", 5),
+				token.NewToken(token.IDENT, "v", 5),
+				token.NewToken(token.TYPEINT8, "int8", 5),
+				token.NewToken(token.EQ, token.EQ, 5),
+				token.NewToken(token.MINUS, token.MINUS, 5),
+				token.NewToken(token.BYTE, "2", 5),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 5),
+				token.NewToken(token.ENUM, "enum", 6),
+				token.NewToken(token.IDENT, "D", 6),
+				token.NewToken(token.LBRACE, token.LBRACE, 6),
+				token.NewToken(token.IDENT, "A", 6),
+				token.NewToken(token.EQ, token.EQ, 6),
+				token.NewToken(token.BYTE, "1", 6),
+				token.NewToken(token.COMMA, token.COMMA, 6),
+				token.NewToken(token.IDENT, "B", 6),
+				token.NewToken(token.RBRACE, token.RBRACE, 6),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 6),
+				token.NewToken(token.IDENT, "x", 7),
+				token.NewToken(token.EQ, token.EQ, 7),
+				token.NewToken(token.LEN, "len", 7),
+				token.NewToken(token.LPAREN, token.LPAREN, 7),
+				token.NewToken(token.IDENT, "a", 7),
+				token.NewToken(token.RPAREN, token.RPAREN, 7),
+				token.NewToken(token.PLUS, token.PLUS, 7),
+				token.NewToken(token.SIZEOF, "sizeof", 7),
+				token.NewToken(token.LPAREN, token.LPAREN, 7),
+				token.NewToken(token.TYPEBYTE, "byte", 7),
+				token.NewToken(token.RPAREN, token.RPAREN, 7),
+				token.NewToken(token.EOF, token.EOF, 7),
+			},
+		},
 	}
 	for i, tt := range cases {
 		input, err := filepath.Abs(tt.fixture)
@@ -171,3 +286,27 @@ func TestNextToken(t *testing.T) {
 
 	}
 }
+
+func TestInvalidLiteral(t *testing.T) {
+	cases := []struct {
+		input          string
+		expectedColumn string
+	}{
+		{input: "let foo = 0xZZ", expectedColumn: "column: 11"},
+		{input: "let foo = 0b102", expectedColumn: "column: 11"},
+		{input: "\n  foo = 1__0", expectedColumn: "column: 9"},
+		{input: "foo = 12_", expectedColumn: "column: 7"},
+		{input: "foo = 'AB'", expectedColumn: "column: 7"},
+		{input: "foo = 'A", expectedColumn: "column: 7"},
+		{input: "sprite {\n\t\"..##\n}", expectedColumn: "column: 2"},
+	}
+	for _, tt := range cases {
+		l := &Lexer{input: tt.input, index: -1}
+		l.readChar()
+		_, err := l.GetTokens()
+		assert.Error(t, err, tt.input)
+		if err != nil {
+			assert.Contains(t, err.Error(), tt.expectedColumn, tt.input)
+		}
+	}
+}
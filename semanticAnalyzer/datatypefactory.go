@@ -3,21 +3,25 @@ package semanticAnalyzer
 import (
 	"errors"
 	"github.com/NoetherianRing/c8-compiler/ast"
+	"github.com/NoetherianRing/c8-compiler/constant"
 	"github.com/NoetherianRing/c8-compiler/errorhandler"
 	"github.com/NoetherianRing/c8-compiler/symboltable"
 	"github.com/NoetherianRing/c8-compiler/token"
 	"strconv"
+	"strings"
 )
 
 type DataTypeFactory struct {
 	scope        *symboltable.Scope
 	ctxNode      *ast.Node
 	walkingAFunc bool
+	declaring    bool //true while the data type of a declaration is being analyzed, where identifiers name struct types
 }
 
 func NewDataTypeFactory() *DataTypeFactory {
 	getter := new(DataTypeFactory)
 	getter.walkingAFunc = false
+	getter.declaring = false
 	getter.ctxNode = nil
 	getter.scope = nil
 	return getter
@@ -44,6 +48,20 @@ func (getter *DataTypeFactory) GetDataType() (interface{}, error) {
 	return get()
 }
 
+//GetDeclaredDataType returns the data type of the declaration led by the current node, whose leaf must be a type.
+//It returns an error if the leaf is an identifier that doesn't name a struct type
+func (getter *DataTypeFactory) GetDeclaredDataType() (interface{}, error) {
+	leaf := GetLeafByRight(getter.ctxNode)
+	if leaf.Value.Type == token.IDENT && !getter.isAType(leaf) {
+		line := leaf.Value.Line
+		return nil, errors.New(errorhandler.NotAType(line, leaf.Value.Literal))
+	}
+	getter.declaring = true
+	datatype, err := getter.GetDataType()
+	getter.declaring = false
+	return datatype, err
+}
+
 //redirect analyzes the token type of the current node of the tree and returns a function that analyzes the data type of the expression led by the node.
 //When needed, it calls another redirect method for further analysis.
 func (getter *DataTypeFactory) redirect() func() (interface{}, error) {
@@ -61,14 +79,22 @@ func (getter *DataTypeFactory) redirect() func() (interface{}, error) {
 		return getter.declarationSimple
 	case token.TYPEBOOL:
 		return getter.declarationSimple
+	case token.TYPEWORD:
+		return getter.declarationSimple
+	case token.TYPEINT8:
+		return getter.declarationSimple
 	case token.IDENT:
-		return getter.reference
+		return getter.redirectIdent()
+	case token.DOT:
+		return getter.field
 	case token.LOR:
 		return getter.logicExpression
 	case token.LAND:
 		return getter.logicExpression
 	case token.BANG:
 		return getter.logicExpression
+	case token.QUESTION:
+		return getter.conditional
 	case token.AND:
 		return getter.bitwiseExpression
 	case token.XOR:
@@ -78,11 +104,13 @@ func (getter *DataTypeFactory) redirect() func() (interface{}, error) {
 	case token.PLUS:
 		return getter.numericOperation
 	case token.MINUS:
-		return getter.numericOperation
+		return getter.redirectMinus()
+	case token.TILDE:
+		return getter.unaryByteOperation
 	case token.LTLT:
-		return getter.byteOperation
+		return getter.shiftOperation
 	case token.GTGT:
-		return getter.byteOperation
+		return getter.shiftOperation
 	case token.SLASH:
 		return getter.byteOperation
 	case token.PERCENT:
@@ -105,6 +133,14 @@ func (getter *DataTypeFactory) redirect() func() (interface{}, error) {
 		return getter.simple
 	case token.BOOL:
 		return getter.simple
+	case token.RBRACE:
+		return getter.arrayLiteral
+	case token.SPRITE:
+		return getter.spriteLiteral
+	case token.LEN:
+		return getter.sizeOperator
+	case token.SIZEOF:
+		return getter.sizeOperator
 	default:
 		panic(errorhandler.UnexpectedCompilerError())
 	}
@@ -130,6 +166,19 @@ func (getter *DataTypeFactory) redirectAsterisk() func() (interface{}, error) {
 
 }
 
+//redirectMinus returns a function that analysis the data type of an expression led by a minus by checking if it is
+//a negation or a subtraction.
+func (getter *DataTypeFactory) redirectMinus() func() (interface{}, error) {
+	switch len(getter.ctxNode.Children) {
+	case 1:
+		return getter.unaryByteOperation
+	case 2:
+		return getter.numericOperation
+	default:
+		panic(errorhandler.UnexpectedCompilerError())
+	}
+}
+
 //redirectBracket returns a function that analysis the data type of an expression led by an bracket by checking the context.
 func (getter *DataTypeFactory) redirectBracket() func() (interface{}, error) {
 	if getter.isADeclarationContext() {
@@ -140,12 +189,24 @@ func (getter *DataTypeFactory) redirectBracket() func() (interface{}, error) {
 
 }
 
+//redirectIdent returns a function that analysis the data type of an identifier by checking if it names a type or a variable
+func (getter *DataTypeFactory) redirectIdent() func() (interface{}, error) {
+	if getter.declaring {
+		return getter.declarationSimple
+	}
+	return getter.reference
+}
+
 //redirectParentheses returns a function that analysis the data type of an expression led by a parentheses by checking the context.
+//A parenthesis is a call if its first child is an identifier, and a conversion if its first child is a data type or the name of a type
 func (getter *DataTypeFactory) redirectParentheses() func() (interface{}, error) {
 	child := getter.ctxNode.Children[0].Value
-	if child.Type == token.IDENT {
+	if child.Type == token.IDENT && !getter.isAType(getter.ctxNode.Children[0]) {
 		return getter.functionCall
 	}
+	if len(getter.ctxNode.Children) == 2 {
+		return getter.conversion
+	}
 	return getter.skipNodeByLeft
 }
 
@@ -155,18 +216,24 @@ func (getter *DataTypeFactory) skipNodeByLeft() (interface{}, error) {
 	return getter.GetDataType()
 }
 
-//isADeclarationContext checks if the leaf after a sequence of nodes has a value of token type "typebool" or "typebyte"
-//to identify if the program is in a context of variable declaration.
+//isADeclarationContext checks if the leaf after a sequence of nodes has a value of token type "typebool", "typebyte", "typeword" or "typeint8",
+//or if it is the name of a struct type in a declaration, to identify if the program is in a context of variable declaration.
 func (getter *DataTypeFactory) isADeclarationContext() bool {
 	//Due to the grammar and the syntax tree, the leaf that would provide information about the context
 	//is going to be found by walking the tree using the right child of each node.
 	leaf := GetLeafByRight(getter.ctxNode)
 	leafType := leaf.Value.Type
 
-	if leafType == token.TYPEBOOL || leafType == token.TYPEBYTE {
+	if leafType == token.TYPEBOOL || leafType == token.TYPEBYTE || leafType == token.TYPEWORD || leafType == token.TYPEINT8 {
 		return true
 	}
-	return false
+	return getter.declaring && leafType == token.IDENT && getter.isAType(leaf)
+}
+
+//isAType checks if an identifier is the name of a struct type
+func (getter *DataTypeFactory) isAType(ident *ast.Node) bool {
+	symbol, ok := getter.scope.Symbols[ident.Value.Literal]
+	return ok && symbol.IsType
 }
 
 //validateParamsDataType validates if the data type of the parameters of a function call match with each data type
@@ -214,11 +281,23 @@ func (getter *DataTypeFactory) validateParamsDataType(args []interface{}) error
 //validateParamDataType  validates if the data type of the expression led by the current "ctxNode"
 //matches the data type of the argument "i" of a function.
 func (getter *DataTypeFactory) validateParamDataType(args []interface{}, i int) error {
+	param := getter.ctxNode
 	treeParam, err := getter.GetDataType()
 	if err != nil {
 		return err
 	}
-	if symboltable.Compare(treeParam, args[i]) {
+	treeParam, err = fitConstant(param, getter.scope, treeParam, args[i])
+	if err != nil {
+		return err
+	}
+	if symboltable.IsAnArray(treeParam) && param.Value.Type != token.SPRITE {
+		getter.ctxNode = param
+		return getter.validateArrayParam(args[i], treeParam)
+	}
+	if symboltable.IsAssignable(args[i], treeParam) {
+		return nil
+	} else if param.Value.Type == token.SPRITE && symboltable.Compare(args[i], symboltable.NewPointer(symboltable.NewByte())) {
+		//sprite literals are saved in the rom, so they can be passed as a pointer to their first row
 		return nil
 	} else {
 
@@ -229,6 +308,22 @@ func (getter *DataTypeFactory) validateParamDataType(args []interface{}, i int)
 
 }
 
+//validateArrayParam validates that an array led by the current "ctxNode" can be passed as a param of the data type arg.
+//The array is passed as a pointer to its first element, so it must be a variable, and the param must be either
+//an array of the same data type, whose length is checked here, or a pointer to the data type of its elements
+func (getter *DataTypeFactory) validateArrayParam(arg interface{}, array interface{}) error {
+	line := getter.ctxNode.Value.Line
+	pointer, isAPointer := arg.(symboltable.Pointer)
+	if !symboltable.Compare(arg, array) &&
+		!(isAPointer && symboltable.Compare(pointer.PointsTo, array.(symboltable.Array).Of)) {
+		return errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(array), token.EQ, symboltable.Fmt(arg)))
+	}
+	if !isAVariable(getter.ctxNode) {
+		return errors.New(errorhandler.NotAnArrayVariable(line, symboltable.Fmt(array)))
+	}
+	return nil
+}
+
 //logicExpression verifies that the expressions led by the ctx Node are boolean and returns a error if not.
 //Otherwise returns a boolean
 func (getter *DataTypeFactory) logicExpression() (interface{}, error) {
@@ -250,8 +345,43 @@ func (getter *DataTypeFactory) logicExpression() (interface{}, error) {
 	return boolType, nil
 }
 
-//comparison verifies that the expressions led by the ctx Node are of the same data type and returns a error if not.
-//Otherwise returns a boolean
+//conditional verifies that the condition of a conditional expression is a boolean, and that both of its branches
+//are of the same data type, which is the one it returns. If a branch is a byte and the other one a word, it returns a word
+func (getter *DataTypeFactory) conditional() (interface{}, error) {
+	const CONDITION = 0
+	const BRANCHES = 1
+	boolType := symboltable.NewBool()
+	backup := getter.ctxNode
+	getter.ctxNode = backup.Children[CONDITION]
+	conditionDataType, err := getter.GetDataType()
+	getter.ctxNode = backup
+	if err != nil {
+		return nil, err
+	}
+	if !boolType.Compare(conditionDataType) {
+		line := getter.ctxNode.Value.Line
+		err := errors.New(errorhandler.UnexpectedDataType(line, symboltable.Fmt(boolType), symboltable.Fmt(conditionDataType)))
+		return nil, err
+	}
+
+	getter.ctxNode = backup.Children[BRANCHES]
+	trueDataType, falseDataType, err := getter.obtainOperandsDatatype()
+	getter.ctxNode = backup
+	if err != nil {
+		return nil, err
+	}
+	trueDataType, falseDataType = getter.adaptConstants(backup.Children[BRANCHES], trueDataType, falseDataType)
+	datatype, ok := symboltable.Widen(trueDataType, falseDataType)
+	if !ok {
+		line := getter.ctxNode.Value.Line
+		err := errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(trueDataType), token.COLON, symboltable.Fmt(falseDataType)))
+		return nil, err
+	}
+	return datatype, nil
+}
+
+//comparison verifies that the expressions led by the ctx Node are of the same data type, or a byte and a word,
+//and returns a error if not. Otherwise returns a boolean
 func (getter *DataTypeFactory) comparison() (interface{}, error) {
 	backup := getter.ctxNode
 	getter.ctxNode = getter.ctxNode.Children[0]
@@ -266,9 +396,21 @@ func (getter *DataTypeFactory) comparison() (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	if leftChildDataType != rightChildDataType {
+	leftChildDataType, rightChildDataType = getter.adaptConstants(backup, leftChildDataType, rightChildDataType)
+	_, ok := symboltable.Widen(leftChildDataType, rightChildDataType)
+	if !ok {
+		line := getter.ctxNode.Value.Line
+		err := errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(leftChildDataType), getter.ctxNode.Value.Literal, symboltable.Fmt(rightChildDataType)))
+		return nil, err
+	}
+	if symboltable.IsAStruct(leftChildDataType) {
+		line := getter.ctxNode.Value.Line
+		err := errors.New(errorhandler.InvalidComparison(line, symboltable.Fmt(leftChildDataType)))
+		return nil, err
+	}
+	if symboltable.IsAnArray(leftChildDataType) && (!isAVariable(backup.Children[0]) || !isAVariable(backup.Children[1])) {
 		line := getter.ctxNode.Value.Line
-		err := errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(leftChildDataType), token.EQ, symboltable.Fmt(rightChildDataType)))
+		err := errors.New(errorhandler.NotAnArrayVariable(line, symboltable.Fmt(leftChildDataType)))
 		return nil, err
 	}
 	return symboltable.NewBool(), nil
@@ -296,19 +438,23 @@ func (getter *DataTypeFactory) bitwiseExpression() (interface{}, error) {
 }
 
 //validateSameNumericDataType verifies that the expressions led by the ctx Node are of the same numeric data type and returns a error if not.
-//Otherwise returns the same datatype of the expressions it leads.
+//Otherwise returns the same datatype of the expressions it leads. A byte and a word are operated as words
 func (getter *DataTypeFactory) validateSameNumericDataType() (interface{}, error) {
 
+	backup := getter.ctxNode
 	leftChildDataType, rightChildDataType, err := getter.obtainOperandsDatatype()
+	getter.ctxNode = backup
 	if err != nil {
 		return nil, err
 	}
-	if !symboltable.Compare(leftChildDataType, rightChildDataType) {
+	leftChildDataType, rightChildDataType = getter.adaptConstants(backup, leftChildDataType, rightChildDataType)
+	datatype, ok := symboltable.Widen(leftChildDataType, rightChildDataType)
+	if !ok {
 		line := getter.ctxNode.Value.Line
-		err := errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(leftChildDataType), token.EQ, symboltable.Fmt(rightChildDataType)))
+		err := errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(leftChildDataType), getter.ctxNode.Value.Literal, symboltable.Fmt(rightChildDataType)))
 		return nil, err
 	} else {
-		if !symboltable.IsNumeric(leftChildDataType) {
+		if !symboltable.IsNumeric(datatype) {
 			line := getter.ctxNode.Value.Line
 			err := errors.New(errorhandler.UnexpectedDataType(line, "numeric", symboltable.Fmt(leftChildDataType)))
 			return nil, err
@@ -316,7 +462,75 @@ func (getter *DataTypeFactory) validateSameNumericDataType() (interface{}, error
 
 	}
 
-	return leftChildDataType, nil
+	return datatype, nil
+}
+
+//adaptConstants adapts the data types of the operands of the operation led by node by calling adaptConstant() with each of them
+func (getter *DataTypeFactory) adaptConstants(node *ast.Node, leftDataType interface{}, rightDataType interface{}) (interface{}, interface{}) {
+	leftDataType = adaptConstant(node.Children[0], getter.scope, leftDataType, rightDataType)
+	rightDataType = adaptConstant(node.Children[1], getter.scope, rightDataType, leftDataType)
+	return leftDataType, rightDataType
+}
+
+//adaptConstant returns an int8 if the expression led by node is a constant byte operated with, or saved in, an int8,
+//so numbers like -2 can be used as signed values without a conversion. If it is operated with, or saved in, a word,
+//its value is computed as a word so it doesn't wrap around as a byte, and it returns a word if that value is greater
+//than 255. Otherwise it returns the data type unchanged. The tree isn't modified: the constants computed as words are
+//replaced by their values once the analysis is over, by SemanticAnalyzer.foldConstants()
+func adaptConstant(node *ast.Node, scope *symboltable.Scope, datatype interface{}, other interface{}) interface{} {
+	if !symboltable.IsByte(datatype) || (!symboltable.IsInt8(other) && !symboltable.IsWord(other)) {
+		return datatype
+	}
+	value, _, reason := constant.Compute(node, scope, symboltable.GetSize(other))
+	if reason > constant.Overflow {
+		return datatype
+	}
+	if symboltable.IsInt8(other) {
+		return symboltable.NewInt8()
+	}
+	if value > 0xFF {
+		return symboltable.NewWord()
+	}
+	return datatype
+}
+
+//fitConstant adapts the data type of a value saved in a variable of the data type target by calling adaptConstant(),
+//and returns an error if the value is a constant that doesn't fit in a byte or an int8 target, which happens when
+//its data type is a word, or when it's out of the range of an int8, from -128 to 127
+func fitConstant(node *ast.Node, scope *symboltable.Scope, datatype interface{}, target interface{}) (interface{}, error) {
+	datatype = adaptConstant(node, scope, datatype, target)
+	if symboltable.IsInt8(target) && symboltable.IsInt8(datatype) {
+		value, reason := constant.Exact(node, scope)
+		if reason == constant.Overflow || (reason == constant.Known && (value < -128 || value > 127)) {
+			line := node.Value.Line
+			return nil, errors.New(errorhandler.ConstantOverflow(line, symboltable.Fmt(target)))
+		}
+	}
+	if !symboltable.IsWord(datatype) || (!symboltable.IsByte(target) && !symboltable.IsInt8(target)) {
+		return datatype, nil
+	}
+	value, isConstant := constant.Evaluate(node, scope)
+	if !isConstant {
+		return datatype, nil
+	}
+	line := node.Value.Line
+	if node.Value.Type == token.BYTE {
+		return nil, errors.New(errorhandler.ByteOutOfRange(line, value))
+	}
+	return nil, errors.New(errorhandler.ConstantOverflow(line, symboltable.Fmt(target)))
+}
+
+//isAVariable checks if the expression led by node is a variable, a dereference or a field, which are the only
+//expressions whose arrays are in memory, so they can be copied and compared in blocks
+func isAVariable(node *ast.Node) bool {
+	switch node.Value.Type {
+	case token.IDENT, token.RBRACKET, token.DOT:
+		return true
+	case token.ASTERISK:
+		return len(node.Children) == 1
+	default:
+		return false
+	}
 }
 
 //obtainOperandDatatype return the datatype of two operands of a operation and an error if needed
@@ -338,18 +552,54 @@ func (getter *DataTypeFactory) obtainOperandsDatatype() (interface{}, interface{
 	return leftChildDataType, rightChildDataType, nil
 }
 
-//numericOperation verifies that the left child of ctx Node is a pointer or a byte and the right child a byte
+//numericOperation verifies that the left child of ctx Node is a pointer and the right child a byte, or that both of them
+//are bytes or words, or both of them int8. The result of an operation between a byte and a word is a word
 func (getter *DataTypeFactory) numericOperation() (interface{}, error) {
+	backup := getter.ctxNode
 	leftChildDataType, rightChildDataType, err := getter.obtainOperandsDatatype()
+	getter.ctxNode = backup
 	if err != nil {
 		return nil, err
 	}
+	leftChildDataType, rightChildDataType = getter.adaptConstants(backup, leftChildDataType, rightChildDataType)
 
 	if !symboltable.IsNumeric(leftChildDataType) {
 		line := getter.ctxNode.Value.Line
 		err := errors.New(errorhandler.UnexpectedDataType(line, "numeric", symboltable.Fmt(leftChildDataType)))
 		return nil, err
 	}
+	_, isAPointer := leftChildDataType.(symboltable.Pointer)
+	if isAPointer || !symboltable.IsInteger(rightChildDataType) {
+		if !symboltable.IsByte(rightChildDataType) {
+			line := getter.ctxNode.Value.Line
+			err := errors.New(errorhandler.UnexpectedDataType(line, "byte", symboltable.Fmt(rightChildDataType)))
+			return nil, err
+		}
+		return leftChildDataType, nil
+	}
+
+	datatype, ok := symboltable.Widen(leftChildDataType, rightChildDataType)
+	if !ok {
+		line := getter.ctxNode.Value.Line
+		err := errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(leftChildDataType), getter.ctxNode.Value.Literal, symboltable.Fmt(rightChildDataType)))
+		return nil, err
+	}
+	return datatype, nil
+}
+
+//shiftOperation verifies that the left child of ctx Node is a byte or a word and the right child, the amount of bits
+//to shift, a byte. Returns the data type of the left child
+func (getter *DataTypeFactory) shiftOperation() (interface{}, error) {
+	leftChildDataType, rightChildDataType, err := getter.obtainOperandsDatatype()
+	if err != nil {
+		return nil, err
+	}
+
+	if !symboltable.IsInteger(leftChildDataType) {
+		line := getter.ctxNode.Value.Line
+		err := errors.New(errorhandler.UnexpectedDataType(line, "byte", symboltable.Fmt(leftChildDataType)))
+		return nil, err
+	}
 	if !symboltable.IsByte(rightChildDataType) {
 		line := getter.ctxNode.Value.Line
 		err := errors.New(errorhandler.UnexpectedDataType(line, "byte", symboltable.Fmt(rightChildDataType)))
@@ -360,13 +610,19 @@ func (getter *DataTypeFactory) numericOperation() (interface{}, error) {
 	return leftChildDataType, nil
 }
 
-//byteOperation verifies that the left  and right children of ctx Node are both bytes
+//byteOperation verifies that the left  and right children of ctx Node are both bytes.
+//Multiplications, divisions and modulos aren't defined for int8, since they are made without a sign
 func (getter *DataTypeFactory) byteOperation() (interface{}, error) {
+	backup := getter.ctxNode
 	leftChildDataType, rightChildDataType, err := getter.obtainOperandsDatatype()
 	if err != nil {
 		return nil, err
 	}
 
+	if symboltable.IsInt8(leftChildDataType) || symboltable.IsInt8(rightChildDataType) {
+		line := backup.Value.Line
+		return nil, errors.New(errorhandler.UndefinedOperator(line, backup.Value.Literal, symboltable.Fmt(symboltable.NewInt8())))
+	}
 	if !symboltable.IsByte(leftChildDataType) {
 		line := getter.ctxNode.Value.Line
 		err := errors.New(errorhandler.UnexpectedDataType(line, "byte", symboltable.Fmt(leftChildDataType)))
@@ -379,14 +635,49 @@ func (getter *DataTypeFactory) byteOperation() (interface{}, error) {
 
 	}
 
+	//a division by zero known at compile time is an error, instead of a value computed when the program runs
+	if _, _, reason := constant.Compute(backup, getter.scope, 1); reason == constant.DivisionByZero {
+		line := backup.Value.Line
+		return nil, errors.New(errorhandler.DivisionByZero(line))
+	}
 	return leftChildDataType, nil
 }
 
+//unaryByteOperation verifies that the only child of ctx Node is a byte or an int8
+func (getter *DataTypeFactory) unaryByteOperation() (interface{}, error) {
+	backup := getter.ctxNode
+	getter.ctxNode = getter.ctxNode.Children[0]
+	childDataType, err := getter.GetDataType()
+	getter.ctxNode = backup
+	if err != nil {
+		return nil, err
+	}
+	if !symboltable.IsByte(childDataType) && !symboltable.IsInt8(childDataType) {
+		line := getter.ctxNode.Value.Line
+		err := errors.New(errorhandler.UnexpectedDataType(line, "byte", symboltable.Fmt(childDataType)))
+		return nil, err
+	}
+	return childDataType, nil
+}
+
 //address takes the data type of what ctxNode dereference and return a pointer that points to
 //that data type
 func (getter *DataTypeFactory) address() (interface{}, error) {
 	getter.ctxNode = getter.ctxNode.Children[0]
-	pointsTo, err := getter.dereference()
+	if getter.ctxNode.Value.Type == token.IDENT {
+		symbol, ok := getter.scope.Symbols[getter.ctxNode.Value.Literal]
+		if ok && symbol.IsConstant {
+			line := getter.ctxNode.Value.Line
+			return nil, errors.New(errorhandler.ConstantModification(line, symbol.Identifier))
+		}
+	}
+	var pointsTo interface{}
+	var err error
+	if getter.ctxNode.Value.Type == token.DOT {
+		pointsTo, err = getter.field()
+	} else {
+		pointsTo, err = getter.dereference()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -447,6 +738,88 @@ func (getter *DataTypeFactory) functionCall() (interface{}, error) {
 
 }
 
+//conversion validates that the value of a conversion can be converted to the data type of its first child, and returns
+//that data type. The allowed conversions are checked by isConvertible()
+func (getter *DataTypeFactory) conversion() (interface{}, error) {
+	const DATATYPE = 0
+	const VALUE = 1
+	backup := getter.ctxNode
+	getter.ctxNode = backup.Children[DATATYPE]
+	datatype, err := getter.GetDeclaredDataType()
+	getter.ctxNode = backup
+	if err != nil {
+		return nil, err
+	}
+	getter.ctxNode = backup.Children[VALUE]
+	valueDataType, err := getter.GetDataType()
+	getter.ctxNode = backup
+	if err != nil {
+		return nil, err
+	}
+	if !isConvertible(datatype, valueDataType) {
+		line := getter.ctxNode.Value.Line
+		return nil, errors.New(errorhandler.InvalidConversion(line, symboltable.Fmt(valueDataType), symboltable.Fmt(datatype)))
+	}
+	return datatype, nil
+}
+
+//isConvertible checks if a value of the data type from can be converted to the data type to. Bytes, words and int8
+//can be converted to each other, booleans to any of them and any of them to booleans. Pointers can be converted
+//to other pointers, to bytes and to words, and bytes and words can be converted to pointers.
+//Enums can be converted to any integer, and any integer to an enum
+func isConvertible(to interface{}, from interface{}) bool {
+	boolType := symboltable.NewBool()
+	_, toPointer := to.(symboltable.Pointer)
+	_, fromPointer := from.(symboltable.Pointer)
+	switch {
+	case symboltable.IsInteger(to) && symboltable.IsInteger(from):
+		return true
+	case toPointer:
+		return fromPointer || symboltable.IsUnsigned(from)
+	case fromPointer:
+		return symboltable.IsUnsigned(to)
+	case boolType.Compare(to):
+		return boolType.Compare(from) || symboltable.IsInteger(from)
+	case boolType.Compare(from):
+		return symboltable.IsInteger(to)
+	case symboltable.IsAnEnum(to):
+		return symboltable.IsInteger(from) || symboltable.Compare(to, from)
+	case symboltable.IsAnEnum(from):
+		return symboltable.IsInteger(to)
+	default:
+		return false
+	}
+}
+
+//field analyzes the data type of the struct whose field is accessed and returns the data type of the field.
+//Pointers to structs are dereferenced, so their fields can be accessed directly
+func (getter *DataTypeFactory) field() (interface{}, error) {
+	const STRUCT = 0
+	const FIELD = 1
+	backup := getter.ctxNode
+	identifier := getter.ctxNode.Children[FIELD].Value.Literal
+	getter.ctxNode = getter.ctxNode.Children[STRUCT]
+	datatype, err := getter.GetDataType()
+	getter.ctxNode = backup
+	if err != nil {
+		return nil, err
+	}
+	pointer, isAPointer := datatype.(symboltable.Pointer)
+	if isAPointer {
+		datatype = pointer.PointsTo
+	}
+	line := getter.ctxNode.Value.Line
+	structure, isAStruct := datatype.(symboltable.Struct)
+	if !isAStruct {
+		return nil, errors.New(errorhandler.UnresolvedField(line, identifier, symboltable.Fmt(datatype)))
+	}
+	field, ok := structure.Field(identifier)
+	if !ok {
+		return nil, errors.New(errorhandler.UnresolvedField(line, identifier, symboltable.Fmt(datatype)))
+	}
+	return field.DataType, nil
+}
+
 //reference checks if an identifier is stored in the symbol table and, if it is, returns its data type.
 //It returns an error in case of not finding the reference, if we are expecting a function and the reference is not a function,
 //or if we are not expecting a function and the reference is a function.
@@ -469,42 +842,42 @@ func (getter *DataTypeFactory) reference() (interface{}, error) {
 			err := errors.New(errorhandler.IdentifierIsFunction(line, literal))
 			return nil, err
 		}
+
+		if ref.IsType {
+			line := getter.ctxNode.Value.Line
+			err := errors.New(errorhandler.TypeIsNotAValue(line, literal))
+			return nil, err
+		}
 	}
 	return ref.DataType, nil
 }
 
 //dereference analyzes the data type of a dereference and returns it
 func (getter *DataTypeFactory) dereference() (interface{}, error) {
-	identifier := GetLeafByRight(getter.ctxNode)
+	base := GetPathBase(getter.ctxNode)
 	backup := getter.ctxNode
+	identifier := base
+	if base.Value.Type == token.RPAREN {
+		identifier = base.Children[0]
+	}
 	if identifier.Value.Type != token.IDENT {
 		line := getter.ctxNode.Value.Line
 		err := errors.New(errorhandler.IdentifierMissed(line))
 		return nil, err
 	}
-	if identifier.Parent != nil {
-		if identifier.Parent.Value.Type == token.RPAREN {
-			getter.ctxNode = identifier.Parent
-		} else {
-			getter.ctxNode = identifier
-		}
-	}
+	getter.ctxNode = base
 	toCompare, err := getter.GetDataType()
 	getter.ctxNode = backup
 	if err != nil {
 		return nil, err
 	}
 
-	for getter.ctxNode != identifier {
+	for getter.ctxNode != base {
 
 		switch toCompare.(type) {
 		case symboltable.Pointer:
 			if getter.ctxNode.Value.Type == token.ASTERISK {
 				getter.ctxNode = getter.ctxNode.Children[0]
-				if getter.ctxNode.Value.Type == token.RPAREN {
-					getter.ctxNode = getter.ctxNode.Children[0]
-
-				}
 				toCompare = toCompare.(symboltable.Pointer).PointsTo
 			} else {
 				line := getter.ctxNode.Value.Line
@@ -522,10 +895,6 @@ func (getter *DataTypeFactory) dereference() (interface{}, error) {
 					return nil, err
 				}
 				getter.ctxNode = getter.ctxNode.Children[1]
-				if getter.ctxNode.Value.Type == token.RPAREN {
-					getter.ctxNode = getter.ctxNode.Children[0]
-
-				}
 				toCompare = toCompare.(symboltable.Array).Of
 
 			} else {
@@ -544,17 +913,21 @@ func (getter *DataTypeFactory) dereference() (interface{}, error) {
 	return toCompare, nil
 }
 
-//validateIndex validates if the index of an array is a byte and if its out of bound.
+//validateIndex validates if the index of an array is a byte literal or a constant and if its out of bound.
 func (getter *DataTypeFactory) validateIndex(compare interface{}) error {
-
-	if getter.ctxNode.Value.Type != token.BYTE {
-		return errors.New(errorhandler.UnexpectedCompilerError())
+	//we first analyze the index to report an invalid expression (its len and sizeof were already replaced by their value)
+	backup := getter.ctxNode
+	_, err := getter.GetDataType()
+	getter.ctxNode = backup
+	if err != nil {
+		return err
+	}
+	length, isConstant := constant.Evaluate(getter.ctxNode, getter.scope)
+	if !isConstant {
+		line := getter.ctxNode.Value.Line
+		return errors.New(errorhandler.NotConstantIndex(line))
 
 	} else {
-		length, err := strconv.Atoi(getter.ctxNode.Value.Literal)
-		if err != nil {
-			panic(errorhandler.UnexpectedCompilerError())
-		}
 		if length < 0 {
 			line := getter.ctxNode.Value.Line
 			err := errors.New(errorhandler.NegativeIndex(line))
@@ -603,15 +976,21 @@ func (getter *DataTypeFactory) declarationFactory() (interface{}, error) {
 	}
 }
 
-//declarationSimple returns a boolean, a byte or a void depending on the context
+//declarationSimple returns a boolean, a byte, a word, an int8, a void or a struct depending on the context
 func (getter *DataTypeFactory) declarationSimple() (interface{}, error) {
 	switch getter.ctxNode.Value.Type {
 	case token.TYPEBOOL:
 		return symboltable.NewBool(), nil
 	case token.TYPEBYTE:
 		return symboltable.NewByte(), nil
+	case token.TYPEWORD:
+		return symboltable.NewWord(), nil
+	case token.TYPEINT8:
+		return symboltable.NewInt8(), nil
 	case token.VOID:
 		return symboltable.NewVoid(), nil
+	case token.IDENT:
+		return getter.scope.Symbols[getter.ctxNode.Value.Literal].DataType, nil
 	default:
 		panic(errorhandler.UnexpectedCompilerError())
 
@@ -640,15 +1019,11 @@ func (getter *DataTypeFactory) declarationFactoryPointer() (interface{}, error)
 //the data type of the elements of the array is obtained by moving the context and calling to declarationFactory()
 func (getter *DataTypeFactory) declarationFactoryArray() (interface{}, error) {
 	index := getter.ctxNode.Children[0]
-	if index.Value.Type != token.BYTE {
-		return nil, errors.New(errorhandler.UnexpectedCompilerError())
-	}
-
-	literal, err := strconv.Atoi(index.Value.Literal)
-	if err != nil {
-		panic(errorhandler.UnexpectedCompilerError())
+	length, isConstant := constant.Evaluate(index, getter.scope)
+	if !isConstant || (index.Value.Type == token.IDENT && !symboltable.IsByte(getter.scope.Symbols[index.Value.Literal].DataType)) {
+		line := getter.ctxNode.Value.Line
+		return nil, errors.New(errorhandler.NotConstantLength(line))
 	}
-	length := literal
 	if length < 0 {
 		line := getter.ctxNode.Value.Line
 		err := errors.New(errorhandler.NegativeIndex(line))
@@ -663,7 +1038,7 @@ func (getter *DataTypeFactory) declarationFactoryArray() (interface{}, error) {
 	return symboltable.NewArray(length, of), nil
 }
 
-//simple returns a boolean or a byte depending on the context
+//simple returns a boolean, a byte or a word depending on the context. Numbers greater than 255 are words
 func (getter *DataTypeFactory) simple() (interface{}, error) {
 	switch getter.ctxNode.Value.Type {
 	case token.BOOL:
@@ -673,11 +1048,18 @@ func (getter *DataTypeFactory) simple() (interface{}, error) {
 		if err != nil {
 			return nil, errors.New(errorhandler.UnexpectedCompilerError())
 		}
-		if _byte > 255 || _byte < 0 {
+		if _byte > 0xFFFF {
+			line := getter.ctxNode.Value.Line
+			return nil, errors.New(errorhandler.WordOutOfRange(line, _byte))
+		}
+		if _byte < 0 {
 			line := getter.ctxNode.Value.Line
 			return nil, errors.New(errorhandler.ByteOutOfRange(line, _byte))
 
 		}
+		if _byte > 255 {
+			return symboltable.NewWord(), nil
+		}
 		return symboltable.NewByte(), nil
 	default:
 		panic(errorhandler.UnexpectedCompilerError())
@@ -685,6 +1067,116 @@ func (getter *DataTypeFactory) simple() (interface{}, error) {
 	}
 }
 
+//sizeOperator validates a len or a sizeof and returns the data type of its value, which is known at compile time
+//and obtained by sizeOperatorValue()
+func (getter *DataTypeFactory) sizeOperator() (interface{}, error) {
+	operator := getter.ctxNode
+	value, err := getter.sizeOperatorValue()
+	if err != nil {
+		return nil, err
+	}
+	getter.ctxNode = ast.NewNode(token.NewToken(token.BYTE, strconv.Itoa(value), operator.Value.Line))
+	datatype, err := getter.simple()
+	getter.ctxNode = operator
+	return datatype, err
+}
+
+//sizeOperatorValue obtains the value of the len or sizeof led by the ctxNode: the length of an array, or the size in bytes
+//of a data type or of the value of an expression. Returns an error if needed
+func (getter *DataTypeFactory) sizeOperatorValue() (int, error) {
+	operator := getter.ctxNode
+	getter.ctxNode = operator.Children[0].Children[0]
+	var datatype interface{}
+	var err error
+	leaf := GetLeafByRight(getter.ctxNode)
+	if getter.isADeclarationContext() || (leaf.Value.Type == token.IDENT && getter.isAType(leaf)) {
+		datatype, err = getter.GetDeclaredDataType()
+	} else {
+		datatype, err = getter.GetDataType()
+	}
+	getter.ctxNode = operator
+	if err != nil {
+		return 0, err
+	}
+
+	if operator.Value.Type == token.LEN {
+		array, isAnArray := datatype.(symboltable.Array)
+		if !isAnArray {
+			line := operator.Value.Line
+			return 0, errors.New(errorhandler.LenOfNonArray(line, symboltable.Fmt(datatype)))
+		}
+		return array.Length, nil
+	}
+	return symboltable.GetSize(datatype), nil
+}
+
+//arrayLiteral verifies that all the elements of an array literal are of the same data type and returns a error if not.
+//Otherwise returns an array of that data type with the length of the literal. If bytes and words are mixed, it is an array of words
+func (getter *DataTypeFactory) arrayLiteral() (interface{}, error) {
+	backup := getter.ctxNode
+	elements := make([]*ast.Node, 0)
+	element := getter.ctxNode.Children[0] //element = comma, literal or array literal
+	for element.Value.Type == token.COMMA {
+		elements = append(elements, element.Children[0])
+		element = element.Children[1]
+	}
+	elements = append(elements, element)
+
+	var of interface{}
+	for _, element = range elements {
+		getter.ctxNode = element
+		elementDataType, err := getter.GetDataType()
+		getter.ctxNode = backup
+		if err != nil {
+			return nil, err
+		}
+		if of == nil {
+			of = elementDataType
+			continue
+		}
+		widened, ok := symboltable.Widen(of, elementDataType)
+		if !ok {
+			line := element.Value.Line
+			err := errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(of), token.COMMA, symboltable.Fmt(elementDataType)))
+			return nil, err
+		}
+		of = widened
+	}
+	return symboltable.NewArray(len(elements), of), nil
+}
+
+//spriteLiteral verifies that the rows of a sprite literal have up to 8 pixels and that it has up to 15 rows,
+//which is the maximum height that can be drawn. Returns an array of bytes, with a byte for each row
+func (getter *DataTypeFactory) spriteLiteral() (interface{}, error) {
+	line := getter.ctxNode.Value.Line
+	rows := constant.SpriteRows(getter.ctxNode)
+	for _, row := range rows {
+		if len(row) > 8 || strings.Trim(row, "#.") != "" {
+			return nil, errors.New(errorhandler.InvalidSpriteRow(line, row))
+		}
+	}
+	if len(rows) > 15 {
+		return nil, errors.New(errorhandler.InvalidSpriteHeight(line, len(rows)))
+	}
+	return symboltable.NewArray(len(rows), symboltable.NewByte()), nil
+}
+
+//GetPathBase walks a sequence of dereferences and indexes led by head and returns the node it starts from,
+//which is an identifier or the call of a function that returns a pointer
+func GetPathBase(head *ast.Node) *ast.Node {
+	current := head
+	for {
+		switch {
+		case current.Value.Type == token.ASTERISK && len(current.Children) == 1:
+			current = current.Children[0]
+		case current.Value.Type == token.RBRACKET:
+			current = current.Children[1]
+		default:
+			return current
+		}
+	}
+}
+
 // GetLeafByRight gets the leaf by walking a tree using the right child of each node.
 func GetLeafByRight(head *ast.Node) *ast.Node {
 	current := head
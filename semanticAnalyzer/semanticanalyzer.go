@@ -2,10 +2,14 @@ package semanticAnalyzer
 
 import (
 	"errors"
+	"github.com/NoetherianRing/c8-compiler/assets"
 	"github.com/NoetherianRing/c8-compiler/ast"
+	"github.com/NoetherianRing/c8-compiler/constant"
 	"github.com/NoetherianRing/c8-compiler/errorhandler"
 	"github.com/NoetherianRing/c8-compiler/symboltable"
 	"github.com/NoetherianRing/c8-compiler/token"
+	"path/filepath"
+	"strconv"
 )
 
 type statementValidator map[token.Type]func() error
@@ -15,6 +19,19 @@ type SemanticAnalyzer struct {
 	validate        statementValidator
 	ctxScope        *symboltable.Scope
 	ctxNode         *ast.Node
+	ctxReturn       interface{}  //the data type that the function being analyzed has to return
+	returns         bool         //returns is true when the last statement analyzed doesn't reach the next one in any path (it returns, breaks or continues)
+	loops           []string     //the labels of the loops that enclose the statement being analyzed, the innermost last
+	ctxLabel        string       //the label of the next loop to be analyzed, empty if it has none
+	sourceDir       string       //the directory of the source file, from which the embedded files are loaded
+	expressions     []expression //the expressions analyzed, whose constants are folded once the analysis is over
+}
+
+//expression is an expression analyzed in a scope, and the data type of the variable its value is saved in, if any
+type expression struct {
+	node   *ast.Node
+	scope  *symboltable.Scope
+	target interface{}
 }
 
 func NewSemanticAnalyzer(tree *ast.SyntaxTree) *SemanticAnalyzer {
@@ -25,15 +42,39 @@ func NewSemanticAnalyzer(tree *ast.SyntaxTree) *SemanticAnalyzer {
 	analyzer.validate = make(statementValidator)
 	analyzer.validate[token.RBRACE] = analyzer.block
 	analyzer.validate[token.LET] = analyzer.let
+	analyzer.validate[token.CONST] = analyzer._const
 	analyzer.validate[token.EQ] = analyzer.assign
+	analyzer.validate[token.PLUSEQ] = analyzer.compoundAssign
+	analyzer.validate[token.MINUSEQ] = analyzer.compoundAssign
+	analyzer.validate[token.ASTERISKEQ] = analyzer.compoundAssign
+	analyzer.validate[token.SLASHEQ] = analyzer.compoundAssign
+	analyzer.validate[token.PERCENTEQ] = analyzer.compoundAssign
+	analyzer.validate[token.ANDEQ] = analyzer.compoundAssign
+	analyzer.validate[token.OREQ] = analyzer.compoundAssign
+	analyzer.validate[token.XOREQ] = analyzer.compoundAssign
+	analyzer.validate[token.LTLTEQ] = analyzer.compoundAssign
+	analyzer.validate[token.GTGTEQ] = analyzer.compoundAssign
+	analyzer.validate[token.PLUSPLUS] = analyzer.compoundAssign
+	analyzer.validate[token.MINUSMINUS] = analyzer.compoundAssign
 	analyzer.validate[token.FUNCTION] = analyzer.fn
 	analyzer.validate[token.RPAREN] = analyzer.call
 	analyzer.validate[token.IF] = analyzer._if
 	analyzer.validate[token.ELSE] = analyzer._else
+	analyzer.validate[token.SWITCH] = analyzer._switch
 	analyzer.validate[token.WHILE] = analyzer._while
+	analyzer.validate[token.FOR] = analyzer._for
+	analyzer.validate[token.RETURN] = analyzer._return
+	analyzer.validate[token.COLON] = analyzer.label
+	analyzer.validate[token.BREAK] = analyzer.loopControl
+	analyzer.validate[token.CONTINUE] = analyzer.loopControl
 	return analyzer
 }
 
+//SetSourceDir sets the directory the paths of the embedded files are relative to
+func (analyzer *SemanticAnalyzer) SetSourceDir(dir string) {
+	analyzer.sourceDir = dir
+}
+
 //Start save in the symbol table the primitive functions, and validates the semantic of global declarations
 //It also checks the declaration of a main function
 func (analyzer *SemanticAnalyzer) Start() (*symboltable.Scope, error) {
@@ -44,71 +85,285 @@ func (analyzer *SemanticAnalyzer) Start() (*symboltable.Scope, error) {
 	globalScope := analyzer.ctxScope
 	block := analyzer.ctxNode.Children[0].Children[0] //The tree start with a "" and a EOF node, so we move
 
+	//we save the global declarations in the order they are written, so a constant can be used by the declarations
+	//after it, like the length of an array field or the value of an enum. They are all saved before the signatures
+	//of the functions, so they can be used in them
 	for _, declaration := range block.Children {
 		analyzer.ctxNode = declaration
-		next := declaration.Value.Type
-		if next != token.FUNCTION && next != token.LET {
+		var err error
+		switch next := declaration.Value.Type; next {
+		case token.FUNCTION:
+			continue
+		case token.ENUM:
+			err = analyzer.declareEnum()
+		case token.TYPE:
+			err = analyzer.declareType()
+		case token.LET, token.CONST:
+			err = analyzer.validate[next]()
+		default:
 			line := analyzer.ctxNode.Value.Line
-			return globalScope, errors.New(errorhandler.GlobalScopeOnlyAllowsDeclarations(line))
+			err = errors.New(errorhandler.GlobalScopeOnlyAllowsDeclarations(line))
 		}
-		err := analyzer.validate[next]()
 		if err != nil {
 			return globalScope, err
 		}
 	}
+
+	//we save the signatures of all the functions before validating any of them, so they can be called
+	//from functions declared before them
+	for _, declaration := range block.Children {
+		if declaration.Value.Type == token.FUNCTION {
+			analyzer.ctxNode = declaration
+			err := analyzer.declareFunction()
+			if err != nil {
+				return globalScope, err
+			}
+		}
+	}
+
+	for _, declaration := range block.Children {
+		if declaration.Value.Type == token.FUNCTION {
+			analyzer.ctxNode = declaration
+			err := analyzer.validate[token.FUNCTION]()
+			if err != nil {
+				return globalScope, err
+			}
+		}
+	}
 	_, existMain := globalScope.Symbols[token.MAIN]
 
 	if !existMain {
 		return globalScope, errors.New(errorhandler.MainFunctionNeeded())
 	}
 
-	return globalScope, nil
+	return globalScope, analyzer.foldConstants()
 }
 
-//block creates a new sub scope and validates the semantic of all the statements within the block
+//block creates a new sub scope and validates the semantic of all the statements within the block.
+//A block returns in all its paths if one of its statements does, in which case the statements after it are unreachable
 func (analyzer *SemanticAnalyzer) block() error {
 	backupScope := analyzer.ctxScope
 	analyzer.ctxScope.AddSubScope()
 	lastAdded := len(analyzer.ctxScope.SubScopes) - 1
 	analyzer.ctxScope = analyzer.ctxScope.SubScopes[lastAdded]
 	block := analyzer.ctxNode
+	blockReturns := false
 	for _, child := range block.Children {
 		analyzer.ctxNode = child
 		next := analyzer.ctxNode.Value.Type
+		if blockReturns {
+			line := analyzer.ctxNode.Value.Line
+			return errors.New(errorhandler.UnreachableCode(line))
+		}
 		//functions only can be declared in the global scope
 		if next == token.FUNCTION {
 			line := analyzer.ctxNode.Value.Line
 			return errors.New(errorhandler.FunctionOutsideGlobalScope(line))
 		}
+		//and so can struct types and enums
+		if next == token.TYPE || next == token.ENUM {
+			line := analyzer.ctxNode.Value.Line
+			return errors.New(errorhandler.TypeOutsideGlobalScope(line))
+		}
+		analyzer.returns = false
 		err := analyzer.validate[next]()
 		if err != nil {
 			return err
 		}
+		blockReturns = analyzer.returns
 	}
+	analyzer.returns = blockReturns
 	analyzer.ctxScope = backupScope
 	return nil
 }
 
 //let validates the semantic of a declaration statements, checks that the name of the declaration is not already in use,
-//and if its not, save the new variable in the symbol table of the current scope
+//and if its not, save the new variable in the symbol table of the current scope.
+//If the variable is initialized, it also checks the data type of the value, or infers the data type of the
+//variable from it when the declaration doesn't have one
 func (analyzer *SemanticAnalyzer) let() error {
-	name := analyzer.ctxNode.Children[0].Value.Literal
-	datatypeTree := analyzer.ctxNode.Children[1]
-	analyzer.updateDataTypeFactoryCtx(datatypeTree)
-	datatype, err := analyzer.datatypeFactory.GetDataType()
-	if err != nil {
-		return err
+	const IDENT = 0
+	const DATATYPE = 1
+	name := analyzer.ctxNode.Children[IDENT].Value.Literal
+	var datatype interface{}
+	if analyzer.ctxNode.Children[DATATYPE].Value.Type != token.EQ {
+		datatypeTree := analyzer.ctxNode.Children[DATATYPE]
+		analyzer.updateDataTypeFactoryCtx(datatypeTree)
+		var err error
+		datatype, err = analyzer.datatypeFactory.GetDeclaredDataType()
+		if err != nil {
+			return err
+		}
+	}
+
+	var data []byte
+	initialization := analyzer.ctxNode.Children[len(analyzer.ctxNode.Children)-1]
+	if initialization.Value.Type == token.EQ {
+		var valueDataType interface{}
+		var err error
+		value := initialization.Children[0]
+		if value.Value.Type == token.EMBED {
+			valueDataType, data, err = analyzer.embed(value)
+		} else {
+			valueDataType, err = analyzer.initialization(value)
+		}
+		if err != nil {
+			return err
+		}
+		if datatype == nil {
+			datatype = valueDataType
+		}
+		if data != nil {
+			//the content of a file is a sequence of bytes, so it can be embedded in any array of bytes with as many
+			//bytes as the file, whatever the number of its dimensions
+			line := analyzer.ctxNode.Value.Line
+			path := value.Children[0].Children[0].Value.Literal
+			if !isAByteArray(datatype) {
+				return errors.New(errorhandler.EmbedNeedsByteArray(line, path, symboltable.Fmt(datatype)))
+			}
+			if symboltable.GetSize(datatype) != len(data) {
+				return errors.New(errorhandler.EmbedSizeMismatch(line, path, len(data), symboltable.Fmt(datatype)))
+			}
+			valueDataType = datatype
+		}
+		valueDataType, err = analyzer.fitConstant(value, valueDataType, datatype)
+		if err != nil {
+			return err
+		}
+		//the value must be of the same data type as the variable, or a byte saved in a word
+		if !symboltable.IsAssignable(datatype, valueDataType) {
+			line := analyzer.ctxNode.Value.Line
+			err = errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(datatype),
+				token.EQ, symboltable.Fmt(valueDataType)))
+			return err
+		}
 	}
 	ok := analyzer.ctxScope.AddSymbol(name, datatype)
 	if !ok {
 		line := analyzer.ctxNode.Value.Line
-		err = errors.New(errorhandler.NameAlreadyInUse(line, name))
+		err := errors.New(errorhandler.NameAlreadyInUse(line, name))
 		return err
 	}
+	analyzer.ctxScope.Symbols[name].Data = data
 
 	return nil
 }
 
+//embed loads the file of an embed expression, relative to the source file, and returns the data type of its content,
+//an array of bytes as long as the content. Because the content is written in the rom, it can only initialize global variables
+func (analyzer *SemanticAnalyzer) embed(value *ast.Node) (interface{}, []byte, error) {
+	line := analyzer.ctxNode.Value.Line
+	path := value.Children[0].Children[0].Value.Literal //the path is the child of the parenthesis
+	if analyzer.ctxScope.Parent != nil {
+		return nil, nil, errors.New(errorhandler.EmbedOutsideGlobalScope(line))
+	}
+	fullPath := path
+	if !filepath.IsAbs(fullPath) {
+		fullPath = filepath.Join(analyzer.sourceDir, path)
+	}
+	data, err := assets.Load(fullPath)
+	if err != nil {
+		return nil, nil, errors.New(errorhandler.InvalidEmbed(line, path, err.Error()))
+	}
+	if len(data) == 0 {
+		return nil, nil, errors.New(errorhandler.InvalidEmbed(line, path, "the file is empty"))
+	}
+	return symboltable.NewArray(len(data), symboltable.NewByte()), data, nil
+}
+
+//isAByteArray checks if a data type is an array of bytes, or an array of arrays of bytes
+func isAByteArray(datatype interface{}) bool {
+	array, ok := datatype.(symboltable.Array)
+	if !ok {
+		return false
+	}
+	return symboltable.IsByte(array.Of) || isAByteArray(array.Of)
+}
+
+//initialization validates the semantic of the value a variable is initialized with and returns its data type.
+//Arrays can only be initialized with array or sprite literals. Because the values of global variables and array literals
+//are written in the rom, they must be known at compile time
+func (analyzer *SemanticAnalyzer) initialization(value *ast.Node) (interface{}, error) {
+	line := analyzer.ctxNode.Value.Line
+	analyzer.updateDataTypeFactoryCtx(value)
+	datatype, err := analyzer.datatypeFactory.GetDataType()
+	if err != nil {
+		return nil, err
+	}
+	isArrayLiteral := value.Value.Type == token.RBRACE || value.Value.Type == token.SPRITE
+	isVoid := symboltable.Compare(datatype, symboltable.NewVoid())
+	isValue := symboltable.IsAnArray(datatype) || symboltable.IsAStruct(datatype)
+	if isVoid || (isValue && !isArrayLiteral) {
+		return nil, errors.New(errorhandler.InvalidInitialization(line, symboltable.Fmt(datatype)))
+	}
+
+	if analyzer.ctxScope.Parent == nil || isArrayLiteral {
+		_, isConstant := constant.Values(value, analyzer.ctxScope, datatype)
+		if !isConstant {
+			return nil, errors.New(errorhandler.NotConstantInitialization(line))
+		}
+	}
+	return datatype, nil
+}
+
+//_const validates the semantic of a constant declaration, computes its value and, if its name is not already in use,
+//saves the constant in the symbol table of the current scope. Constants can only be bytes, words, int8 or booleans
+func (analyzer *SemanticAnalyzer) _const() error {
+	const IDENT = 0
+	const DATATYPE = 1
+	name := analyzer.ctxNode.Children[IDENT].Value.Literal
+	line := analyzer.ctxNode.Value.Line
+	value := analyzer.ctxNode.Children[len(analyzer.ctxNode.Children)-1].Children[0]
+
+	var valueDataType interface{}
+	var err error
+	if value.Value.Type == token.EMBED {
+		valueDataType, _, err = analyzer.embed(value)
+	} else {
+		analyzer.updateDataTypeFactoryCtx(value)
+		valueDataType, err = analyzer.datatypeFactory.GetDataType()
+	}
+	if err != nil {
+		return err
+	}
+	if !symboltable.IsInteger(valueDataType) && !symboltable.Compare(valueDataType, symboltable.NewBool()) {
+		return errors.New(errorhandler.InvalidConstant(line, symboltable.Fmt(valueDataType)))
+	}
+
+	datatype := valueDataType
+	if analyzer.ctxNode.Children[DATATYPE].Value.Type != token.EQ {
+		analyzer.updateDataTypeFactoryCtx(analyzer.ctxNode.Children[DATATYPE])
+		datatype, err = analyzer.datatypeFactory.GetDeclaredDataType()
+		if err != nil {
+			return err
+		}
+		valueDataType, err = analyzer.fitConstant(value, valueDataType, datatype)
+		if err != nil {
+			return err
+		}
+		if !symboltable.IsAssignable(datatype, valueDataType) {
+			err = errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(datatype),
+				token.EQ, symboltable.Fmt(valueDataType)))
+			return err
+		}
+	}
+
+	computed, _, reason := constant.Compute(value, analyzer.ctxScope, symboltable.GetSize(datatype))
+	switch reason {
+	case constant.Unknown:
+		return errors.New(errorhandler.NotConstantValue(line, name))
+	case constant.DivisionByZero:
+		return errors.New(errorhandler.DivisionByZero(line))
+	case constant.Overflow:
+		return errors.New(errorhandler.ConstantOverflow(line, symboltable.Fmt(datatype)))
+	}
+	ok := analyzer.ctxScope.AddConstant(name, datatype, computed)
+	if !ok {
+		return errors.New(errorhandler.NameAlreadyInUse(line, name))
+	}
+	return nil
+}
+
 //assign validates the semantic of assignation statements
 func (analyzer *SemanticAnalyzer) assign() error {
 	leftTree := analyzer.ctxNode.Children[0]
@@ -118,22 +373,43 @@ func (analyzer *SemanticAnalyzer) assign() error {
 	if err != nil {
 		return err
 	}
-	if symboltable.IsAnArray(leftDataType) {
+	if symboltable.IsAStruct(leftDataType) {
 		line := analyzer.ctxNode.Value.Line
 		err = errors.New(errorhandler.InvalidAssignation(line, symboltable.Fmt(leftDataType)))
 		return err
 
 	}
+	if leftTree.Value.Type == token.IDENT && analyzer.ctxScope.Symbols[leftTree.Value.Literal].IsConstant {
+		line := analyzer.ctxNode.Value.Line
+		return errors.New(errorhandler.ConstantModification(line, leftTree.Value.Literal))
+	}
 
 	rightTree := analyzer.ctxNode.Children[1]
 	analyzer.updateDataTypeFactoryCtx(rightTree)
 	rightDataType, err := analyzer.datatypeFactory.GetDataType()
 
+	if err != nil {
+		return err
+	}
+	rightDataType, err = analyzer.fitConstant(rightTree, rightDataType, leftDataType)
 	if err != nil {
 		return err
 	}
 
-	if !symboltable.Compare(leftDataType, rightDataType) {
+	//arrays are copied in blocks, so both of them must be of the same data type and be in memory
+	if symboltable.IsAnArray(leftDataType) {
+		line := analyzer.ctxNode.Value.Line
+		if !symboltable.Compare(leftDataType, rightDataType) {
+			return errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(leftDataType),
+				token.EQ, symboltable.Fmt(rightDataType)))
+		}
+		if !isAVariable(rightTree) {
+			return errors.New(errorhandler.NotAnArrayVariable(line, symboltable.Fmt(leftDataType)))
+		}
+		return nil
+	}
+
+	if !symboltable.IsAssignable(leftDataType, rightDataType) {
 		line := analyzer.ctxNode.Value.Line
 		err = errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(leftDataType),
 			token.EQ, symboltable.Fmt(rightDataType)))
@@ -142,66 +418,204 @@ func (analyzer *SemanticAnalyzer) assign() error {
 	return nil
 }
 
-//fn validates the semantic of the declaration of a function,
-//then checks that the name of the declaration is not already in use,
-//and if its not, save the new variable in the symbol table of the current scope
-func (analyzer *SemanticAnalyzer) fn() error {
+//compoundAssign validates the semantic of compound assignations (like x += 2), increments and decrements,
+//which are valid if the assignation of their operation to the variable is (like x = x + 2)
+func (analyzer *SemanticAnalyzer) compoundAssign() error {
+	const TARGET = 0
+	operation := ast.NewCompoundOperation(analyzer.ctxNode)
+	assignation := ast.NewNode(token.NewToken(token.EQ, token.EQ, analyzer.ctxNode.Value.Line))
+	assignation.Children = []*ast.Node{analyzer.ctxNode.Children[TARGET], operation}
+	analyzer.ctxNode = assignation
+	return analyzer.assign()
+}
+
+//declareFunction obtains the signature of a function and, if its name is not already in use,
+//saves the function in the symbol table of the current scope
+func (analyzer *SemanticAnalyzer) declareFunction() error {
 	const IDENT = 0
 	const PARAMS = 1
 	const DATATYPERETURN = 2
-	const BLOCK = 3
-	backupNode := analyzer.ctxNode
-	backupScope := analyzer.ctxScope
-	analyzer.ctxNode = analyzer.ctxNode.Children[PARAMS]
-	args, err := analyzer.handleParams()
-	if err != nil {
-		return err
+	const DATATYPE = 1
+	var args []interface{}
+	if len(analyzer.ctxNode.Children[PARAMS].Children) != 0 {
+		params := make([]*ast.Node, 0)
+		param := analyzer.ctxNode.Children[PARAMS].Children[0] //param = comma or let
+		for param.Value.Type == token.COMMA {
+			params = append(params, param.Children[0])
+			param = param.Children[1]
+		}
+		params = append(params, param)
+
+		args = make([]interface{}, 0)
+		for _, param = range params {
+			analyzer.updateDataTypeFactoryCtx(param.Children[DATATYPE])
+			datatype, err := analyzer.datatypeFactory.GetDeclaredDataType()
+			if err != nil {
+				return err
+			}
+			args = append(args, datatype)
+		}
 	}
-	analyzer.ctxNode = backupNode
-	analyzer.ctxScope = backupScope
 
 	name := analyzer.ctxNode.Children[IDENT].Value.Literal
 	analyzer.updateDataTypeFactoryCtx(analyzer.ctxNode.Children[DATATYPERETURN])
 
-	expectedReturnDataType, err := analyzer.datatypeFactory.GetDataType()
+	returnDataType, err := analyzer.datatypeFactory.GetDeclaredDataType()
 	if err != nil {
 		return err
 	}
-	function := symboltable.NewFunction(expectedReturnDataType, args)
+
+	//the return value is saved in v0, or in v0 and v1 if it has two bytes, so arrays and structs can't be returned
+	switch returnDataType.(type) {
+	case symboltable.Simple, symboltable.Pointer, symboltable.Enum:
+	default:
+		line := analyzer.ctxNode.Value.Line
+		err = errors.New(errorhandler.InvalidReturnType(line, symboltable.Fmt(returnDataType)))
+		return err
+
+	}
+
+	function := symboltable.NewFunction(returnDataType, args)
 	ok := analyzer.ctxScope.AddSymbol(name, function)
 	if !ok {
 		line := analyzer.ctxNode.Value.Line
 		err = errors.New(errorhandler.NameAlreadyInUse(line, name))
 		return err
 	}
+	return nil
+}
 
-	if args != nil {
+//declareType validates the fields of a struct type and, if its name is not already in use,
+//saves the struct in the symbol table of the current scope. The identifiers and the data types of the fields
+//are the children of the struct, one after the other
+func (analyzer *SemanticAnalyzer) declareType() error {
+	const IDENT = 0
+	const FIELDS = 1
+	name := analyzer.ctxNode.Children[IDENT].Value.Literal
+	fields := analyzer.ctxNode.Children[FIELDS].Children
 
-		lastAdded := len(analyzer.ctxScope.SubScopes) - 1
-		analyzer.ctxScope = analyzer.ctxScope.SubScopes[lastAdded]
-		analyzer.ctxScope.AddSymbol(name, function)
+	//the struct is saved before its fields are known, so a field can point to it, like the next node of a list
+	structure := symboltable.NewEmptyStruct(name, len(fields)/2)
+	ok := analyzer.ctxScope.AddType(name, structure)
+	if !ok {
+		line := analyzer.ctxNode.Value.Line
+		return errors.New(errorhandler.NameAlreadyInUse(line, name))
 	}
 
-	if !symboltable.Compare(expectedReturnDataType, symboltable.NewVoid()) &&
-		!symboltable.Compare(expectedReturnDataType, symboltable.NewBool()) &&
-		!symboltable.Compare(expectedReturnDataType, symboltable.NewByte()) {
+	identifiers := make([]string, 0)
+	datatypes := make([]interface{}, 0)
+	for i := 0; i < len(fields); i += 2 {
+		identifier := fields[i].Value.Literal
+		line := fields[i].Value.Line
+		for _, declared := range identifiers {
+			if declared == identifier {
+				return errors.New(errorhandler.NameAlreadyInUse(line, identifier))
+			}
+		}
+		analyzer.updateDataTypeFactoryCtx(fields[i+1])
+		datatype, err := analyzer.datatypeFactory.GetDeclaredDataType()
+		if err != nil {
+			return err
+		}
+		of := datatype
+		for symboltable.IsAnArray(of) {
+			of = of.(symboltable.Array).Of
+		}
+		if structure.Compare(of) {
+			return errors.New(errorhandler.StructContainsItself(line, name))
+		}
+		identifiers = append(identifiers, identifier)
+		datatypes = append(datatypes, datatype)
+	}
+	structure.SetFields(identifiers, datatypes)
+	return nil
+}
 
+//declareEnum saves an enum type in the symbol table of the current scope, and each of its values as a constant of that type.
+//A value is one more than the previous one, starting from 0, unless it is given explicitly by a constant byte
+func (analyzer *SemanticAnalyzer) declareEnum() error {
+	const IDENT = 0
+	const VALUES = 1
+	name := analyzer.ctxNode.Children[IDENT].Value.Literal
+	enum := symboltable.NewEnum(name)
+	ok := analyzer.ctxScope.AddType(name, enum)
+	if !ok {
 		line := analyzer.ctxNode.Value.Line
-		err = errors.New(errorhandler.InvalidReturnType(line, symboltable.Fmt(expectedReturnDataType)))
+		return errors.New(errorhandler.NameAlreadyInUse(line, name))
+	}
+
+	next := 0
+	for _, value := range constant.EnumValues(analyzer.ctxNode.Children[VALUES]) {
+		identifier := value
+		line := value.Value.Line
+		if value.Value.Type == token.EQ {
+			identifier = value.Children[0]
+			explicit := value.Children[1]
+			analyzer.updateDataTypeFactoryCtx(explicit)
+			datatype, err := analyzer.datatypeFactory.GetDataType()
+			if err != nil {
+				return err
+			}
+			datatype, err = analyzer.fitConstant(explicit, datatype, symboltable.NewByte())
+			if err != nil {
+				return err
+			}
+			if !symboltable.IsByte(datatype) {
+				return errors.New(errorhandler.UnexpectedDataType(line, "byte", symboltable.Fmt(datatype)))
+			}
+			computed, isConstant := constant.Evaluate(explicit, analyzer.ctxScope)
+			if !isConstant {
+				return errors.New(errorhandler.NotConstantValue(line, identifier.Value.Literal))
+			}
+			next = computed
+		}
+		if next > 0xFF {
+			return errors.New(errorhandler.ByteOutOfRange(line, next))
+		}
+		ok = analyzer.ctxScope.AddConstant(identifier.Value.Literal, enum, next)
+		if !ok {
+			return errors.New(errorhandler.NameAlreadyInUse(line, identifier.Value.Literal))
+		}
+		next++
+	}
+	return nil
+}
+
+//fn validates the semantic of the declaration of a function, whose signature was already saved in the symbol table
+//of the current scope by declareFunction
+func (analyzer *SemanticAnalyzer) fn() error {
+	const IDENT = 0
+	const PARAMS = 1
+	const BLOCK = 3
+	backupNode := analyzer.ctxNode
+	backupScope := analyzer.ctxScope
+	analyzer.ctxNode = analyzer.ctxNode.Children[PARAMS]
+	args, err := analyzer.handleParams()
+	if err != nil {
 		return err
+	}
+	analyzer.ctxNode = backupNode
+
+	name := analyzer.ctxNode.Children[IDENT].Value.Literal
+	expectedReturnDataType := backupScope.Symbols[name].DataType.(symboltable.Function).Return
 
+	if args != nil {
+		lastAdded := len(backupScope.SubScopes) - 1
+		analyzer.ctxScope = backupScope.SubScopes[lastAdded]
 	}
+
 	analyzer.ctxNode = analyzer.ctxNode.Children[BLOCK]
+	analyzer.ctxReturn = expectedReturnDataType
 
-	actualReturnDataType, err := analyzer.funcBlock()
+	err = analyzer.block()
 	if err != nil {
 		return err
 	}
 
-	if !symboltable.Compare(expectedReturnDataType, actualReturnDataType) {
-		line := analyzer.ctxNode.Value.Line
-		err = errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(expectedReturnDataType),
-			token.EQ, symboltable.Fmt(actualReturnDataType)))
+	//void functions return at the end of the block, the rest of them need a return statement in all the paths
+	if !analyzer.returns && !symboltable.Compare(expectedReturnDataType, symboltable.NewVoid()) {
+		line := backupNode.Value.Line
+		err = errors.New(errorhandler.MissingReturn(line, name))
 		return err
 	}
 	analyzer.ctxNode = backupNode
@@ -223,37 +637,222 @@ func (analyzer *SemanticAnalyzer) call() error {
 	return nil
 }
 
-//_if validates the semantic of if statements
+//_return validates that the data type of the value returned matches the one of the function being analyzed
+func (analyzer *SemanticAnalyzer) _return() error {
+	var actualReturnDataType interface{}
+	actualReturnDataType = symboltable.NewVoid()
+	if len(analyzer.ctxNode.Children) != 0 {
+		analyzer.updateDataTypeFactoryCtx(analyzer.ctxNode.Children[0])
+		datatype, err := analyzer.datatypeFactory.GetDataType()
+		if err != nil {
+			return err
+		}
+		actualReturnDataType, err = analyzer.fitConstant(analyzer.ctxNode.Children[0], datatype, analyzer.ctxReturn)
+		if err != nil {
+			return err
+		}
+	}
+
+	//a byte can be returned as a word, and a sprite literal as a pointer to its first row, as when they are passed as params
+	isASprite := len(analyzer.ctxNode.Children) != 0 && analyzer.ctxNode.Children[0].Value.Type == token.SPRITE
+	if !symboltable.IsAssignable(analyzer.ctxReturn, actualReturnDataType) &&
+		!(isASprite && symboltable.Compare(analyzer.ctxReturn, symboltable.NewPointer(symboltable.NewByte()))) {
+		line := analyzer.ctxNode.Value.Line
+		err := errors.New(errorhandler.DataTypesMismatch(line, symboltable.Fmt(analyzer.ctxReturn),
+			token.EQ, symboltable.Fmt(actualReturnDataType)))
+		return err
+	}
+	analyzer.returns = true
+	return nil
+}
+
+//_if validates the semantic of if statements. An if statement doesn't return in all its paths, because its block
+//could not be executed
 func (analyzer *SemanticAnalyzer) _if() error {
-	return analyzer.validateConditionAndBlock()
+	err := analyzer.validateConditionAndBlock()
+	analyzer.returns = false
+	return err
 }
 
-//_else validates the semantic of if/else statements
+//_else validates the semantic of if/else statements. It returns in all its paths only if both of its blocks do
 func (analyzer *SemanticAnalyzer) _else() error {
 	backup := analyzer.ctxNode
 	err := analyzer.validateConditionAndBlock()
 	if err != nil {
 		return err
 	}
+	ifReturns := analyzer.returns
 
 	analyzer.ctxNode = backup
 	analyzer.ctxNode = analyzer.ctxNode.Children[2]
 	elseBlock := analyzer.ctxNode.Value.Type
 	err = analyzer.validate[elseBlock]()
+	analyzer.returns = ifReturns && analyzer.returns
 	return err
 }
 
-//_while validates the semantic of while statements
+//_switch validates that the subject of a switch statement is a byte, an int8 or an enum, that the values of its cases are of the same
+//data type, known at compile time and handled only once, and that it has at most one default. Then it validates the block of each clause.
+//A switch returns in all its paths only if it has a default and all of its blocks return
+func (analyzer *SemanticAnalyzer) _switch() error {
+	const SUBJECT = 0
+	const CLAUSES = 1
+	byteDatatype := symboltable.NewByte()
+	switchNode := analyzer.ctxNode
+	analyzer.updateDataTypeFactoryCtx(switchNode.Children[SUBJECT])
+	datatypeSubject, err := analyzer.datatypeFactory.GetDataType()
+	if err != nil {
+		return err
+	}
+	if !byteDatatype.Compare(datatypeSubject) && !symboltable.IsInt8(datatypeSubject) && !symboltable.IsAnEnum(datatypeSubject) {
+		line := switchNode.Value.Line
+		return errors.New(errorhandler.UnexpectedDataType(line, symboltable.Fmt(byteDatatype), symboltable.Fmt(datatypeSubject)))
+	}
+
+	handled := make(map[int]bool)
+	hasDefault := false
+	allReturn := true
+	for _, clause := range switchNode.Children[CLAUSES].Children {
+		line := clause.Value.Line
+		if clause.Value.Type == token.DEFAULT {
+			if hasDefault {
+				return errors.New(errorhandler.MultipleDefaults(line))
+			}
+			hasDefault = true
+		} else {
+			for _, value := range constant.CaseValues(clause) {
+				analyzer.updateDataTypeFactoryCtx(value)
+				datatypeValue, err := analyzer.datatypeFactory.GetDataType()
+				if err != nil {
+					return err
+				}
+				//the values of the cases of an int8 can be written as numbers, like -1
+				datatypeValue, err = analyzer.fitConstant(value, datatypeValue, datatypeSubject)
+				if err != nil {
+					return err
+				}
+				if !symboltable.Compare(datatypeSubject, datatypeValue) {
+					return errors.New(errorhandler.UnexpectedDataType(line, symboltable.Fmt(datatypeSubject), symboltable.Fmt(datatypeValue)))
+				}
+				constantValue, isConstant := constant.Evaluate(value, analyzer.ctxScope)
+				if !isConstant {
+					return errors.New(errorhandler.NotConstantCase(line))
+				}
+				if handled[constantValue] {
+					return errors.New(errorhandler.DuplicateCase(line, constantValue))
+				}
+				handled[constantValue] = true
+			}
+		}
+		analyzer.ctxNode = clause.Children[len(clause.Children)-1]
+		err = analyzer.validate[analyzer.ctxNode.Value.Type]()
+		if err != nil {
+			return err
+		}
+		allReturn = allReturn && analyzer.returns
+	}
+	analyzer.returns = hasDefault && allReturn
+	return nil
+}
+
+//_while validates the semantic of while statements. A while statement doesn't return in all its paths,
+//because its block could not be executed
 func (analyzer *SemanticAnalyzer) _while() error {
-	return analyzer.validateConditionAndBlock()
+	analyzer.loops = append(analyzer.loops, analyzer.ctxLabel)
+	analyzer.ctxLabel = ""
+	err := analyzer.validateConditionAndBlock()
+	analyzer.loops = analyzer.loops[:len(analyzer.loops)-1]
+	analyzer.returns = false
+	return err
+}
+
+//_for validates the semantic of for statements. The variable declared in the initialization lives in a scope of the loop,
+//which encloses the scope of the block. Like a while, a for statement doesn't return in all its paths
+func (analyzer *SemanticAnalyzer) _for() error {
+	const INIT = 0
+	const CONDITION = 1
+	const STEP = 2
+	const BLOCK = 3
+	backupScope := analyzer.ctxScope
+	analyzer.ctxScope.AddSubScope()
+	lastAdded := len(analyzer.ctxScope.SubScopes) - 1
+	analyzer.ctxScope = analyzer.ctxScope.SubScopes[lastAdded]
+	forNode := analyzer.ctxNode
+
+	analyzer.ctxNode = forNode.Children[INIT]
+	err := analyzer.validate[analyzer.ctxNode.Value.Type]()
+	if err != nil {
+		return err
+	}
+	analyzer.ctxNode = forNode
+	err = analyzer.validateCondition(forNode.Children[CONDITION])
+	if err != nil {
+		return err
+	}
+	analyzer.ctxNode = forNode.Children[STEP]
+	err = analyzer.validate[analyzer.ctxNode.Value.Type]()
+	if err != nil {
+		return err
+	}
+
+	analyzer.loops = append(analyzer.loops, analyzer.ctxLabel)
+	analyzer.ctxLabel = ""
+	analyzer.ctxNode = forNode.Children[BLOCK]
+	err = analyzer.validate[analyzer.ctxNode.Value.Type]()
+	analyzer.loops = analyzer.loops[:len(analyzer.loops)-1]
+	analyzer.returns = false
+	analyzer.ctxScope = backupScope
+	return err
+}
+
+//label validates the semantic of a labeled loop. Labels aren't symbols of the scope, so they don't clash with the names
+//of variables, and a nested loop can reuse the label of an enclosing one, which break and continue no longer reach
+func (analyzer *SemanticAnalyzer) label() error {
+	const LABEL = 0
+	const LOOP = 1
+	analyzer.ctxLabel = analyzer.ctxNode.Children[LABEL].Value.Literal
+	analyzer.ctxNode = analyzer.ctxNode.Children[LOOP]
+	return analyzer.validate[analyzer.ctxNode.Value.Type]()
+}
+
+//loopControl validates that a break or a continue is inside a loop, and if it has a label,
+//that one of the enclosing loops has that label
+func (analyzer *SemanticAnalyzer) loopControl() error {
+	line := analyzer.ctxNode.Value.Line
+	if len(analyzer.loops) == 0 {
+		return errors.New(errorhandler.LoopControlOutsideLoop(line, analyzer.ctxNode.Value.Literal))
+	}
+	if len(analyzer.ctxNode.Children) != 0 {
+		label := analyzer.ctxNode.Children[0].Value.Literal
+		found := false
+		for _, enclosing := range analyzer.loops {
+			found = found || enclosing == label
+		}
+		if !found {
+			return errors.New(errorhandler.UnresolvedLabel(line, label))
+		}
+	}
+	//the statements after a break or a continue are unreachable
+	analyzer.returns = true
+	return nil
 }
 
 //validateConditionAndBlock validates that the condition of a statement such as if, if/else and while
 //is a boolean expression, and then executes the block of the statement
 func (analyzer *SemanticAnalyzer) validateConditionAndBlock() error {
-	boolDatatype := symboltable.NewBool()
-	condition := analyzer.ctxNode.Children[0]
+	err := analyzer.validateCondition(analyzer.ctxNode.Children[0])
+	if err != nil {
+		return err
+	}
+	analyzer.ctxNode = analyzer.ctxNode.Children[1]
+	block := analyzer.ctxNode.Value.Type
+	err = analyzer.validate[block]()
+	return err
+}
 
+//validateCondition validates that the condition of the statement being analyzed is a boolean expression
+func (analyzer *SemanticAnalyzer) validateCondition(condition *ast.Node) error {
+	boolDatatype := symboltable.NewBool()
 	analyzer.updateDataTypeFactoryCtx(condition)
 	datatypeCondition, err := analyzer.datatypeFactory.GetDataType()
 	if err != nil {
@@ -261,13 +860,9 @@ func (analyzer *SemanticAnalyzer) validateConditionAndBlock() error {
 	}
 	if !boolDatatype.Compare(datatypeCondition) {
 		line := analyzer.ctxNode.Value.Line
-		err = errors.New(errorhandler.UnexpectedDataType(line, symboltable.Fmt(boolDatatype), symboltable.Fmt(datatypeCondition)))
-		return err
+		return errors.New(errorhandler.UnexpectedDataType(line, symboltable.Fmt(boolDatatype), symboltable.Fmt(datatypeCondition)))
 	}
-	analyzer.ctxNode = analyzer.ctxNode.Children[1]
-	block := analyzer.ctxNode.Value.Type
-	err = analyzer.validate[block]()
-	return err
+	return nil
 }
 
 //savePrimitiveFunctions save into the symbol table the primitive functions of the language
@@ -359,41 +954,179 @@ func (analyzer *SemanticAnalyzer) saveIsKeyPressed() bool {
 	return analyzer.ctxScope.AddSymbol(symboltable.FunctionIsKeyPressed, functionType)
 }
 
-//updateDataTypeFactoryCtx updates the context of datatypeFactory
+//updateDataTypeFactoryCtx updates the context of datatypeFactory, and saves the expression to analyze so its constants
+//are checked once the analysis is over. Its len and sizeof operators are replaced by their values before
 func (analyzer *SemanticAnalyzer) updateDataTypeFactoryCtx(toAnalyze *ast.Node) {
+	analyzer.replaceSizeOperators(toAnalyze)
 	analyzer.datatypeFactory.SetCxtNode(toAnalyze)
 	analyzer.datatypeFactory.SetScope(analyzer.ctxScope)
+	analyzer.expressions = append(analyzer.expressions, expression{toAnalyze, analyzer.ctxScope, nil})
 }
 
-//funcBlock adds a new sub scope and validates the semantic of all the statements within the block,
-//then returns the data type of the return statement
-func (analyzer *SemanticAnalyzer) funcBlock() (interface{}, error) {
-	analyzer.ctxScope.AddSubScope()
-	lastAdded := len(analyzer.ctxScope.SubScopes) - 1
-	analyzer.ctxScope = analyzer.ctxScope.SubScopes[lastAdded]
-	block := analyzer.ctxNode
-	for _, child := range block.Children {
-		if child.Value.Type != token.RETURN {
-			analyzer.ctxNode = child
-			next := analyzer.ctxNode.Value.Type
-			if next == token.FUNCTION {
-				line := analyzer.ctxNode.Value.Line
-				return nil, errors.New(errorhandler.FunctionOutsideGlobalScope(line))
-			}
-			err := analyzer.validate[next]()
-			if err != nil {
-				return nil, err
+//replaceSizeOperators replaces each len and sizeof of the expression led by node by its value, which is known at compile
+//time, so from then on it is a constant, emitted as an immediate. An operator whose value can't be obtained is kept,
+//so the analysis of its data type reports why
+func (analyzer *SemanticAnalyzer) replaceSizeOperators(node *ast.Node) {
+	for _, child := range node.Children {
+		analyzer.replaceSizeOperators(child)
+	}
+	if node.Value.Type != token.LEN && node.Value.Type != token.SIZEOF {
+		return
+	}
+	analyzer.datatypeFactory.SetCxtNode(node)
+	analyzer.datatypeFactory.SetScope(analyzer.ctxScope)
+	value, err := analyzer.datatypeFactory.sizeOperatorValue()
+	if err != nil {
+		return
+	}
+	node.Value = token.NewToken(token.BYTE, strconv.Itoa(value), node.Value.Line)
+	node.Children = make([]*ast.Node, 0)
+}
+
+//fitConstant calls fitConstant() with a value saved in a variable of the data type target, and saves that data type
+//as the one of the expression, so its constants are folded as values of that data type
+func (analyzer *SemanticAnalyzer) fitConstant(value *ast.Node, datatype interface{}, target interface{}) (interface{}, error) {
+	for i := len(analyzer.expressions) - 1; i >= 0; i-- {
+		if analyzer.expressions[i].node == value {
+			analyzer.expressions[i].target = target
+			break
+		}
+	}
+	return fitConstant(value, analyzer.ctxScope, datatype, target)
+}
+
+//foldConstants walks all the expressions analyzed once the analysis is over, when their data types are known,
+//to check that their constants fit in their data types and to replace the constants computed as words by their values
+func (analyzer *SemanticAnalyzer) foldConstants() error {
+	for _, expression := range analyzer.expressions {
+		analyzer.datatypeFactory.SetScope(expression.scope)
+		err := analyzer.foldConstant(expression.node, expression.target)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//foldConstant checks that the value of the constant expression led by node, which is computed at compile time, fits in
+//its data type. A byte constant saved in, or operated with, a word, which is the target, is computed as a word so it
+//doesn't wrap around as a byte, and it is replaced in the tree by a number with its value.
+//If the expression isn't a constant, it folds the constants of its operands.
+func (analyzer *SemanticAnalyzer) foldConstant(node *ast.Node, target interface{}) error {
+	scope := analyzer.datatypeFactory.scope
+	_, _, reason := constant.Compute(node, scope, 1)
+	if reason <= constant.Overflow {
+		datatype, err := analyzer.dataTypeOf(node)
+		if err != nil {
+			return err
+		}
+		if symboltable.IsByte(datatype) && symboltable.IsWord(target) {
+			datatype = target
+		}
+		if !symboltable.IsWord(datatype) {
+			if reason == constant.Overflow {
+				return errors.New(errorhandler.ConstantOverflow(node.Value.Line, symboltable.Fmt(datatype)))
 			}
-		} else {
-			if len(child.Children) != 0 {
-				toAnalyze := child.Children[0]
-				analyzer.updateDataTypeFactoryCtx(toAnalyze)
-				return analyzer.datatypeFactory.GetDataType()
+			return nil
+		}
+		value, _, wordReason := constant.Compute(node, scope, 2)
+		if wordReason == constant.Overflow {
+			return errors.New(errorhandler.ConstantOverflow(node.Value.Line, symboltable.Fmt(datatype)))
+		}
+		if reason == constant.Overflow {
+			//as a byte it wraps around, so the emitter would compute another value
+			node.Value = token.NewToken(token.BYTE, strconv.Itoa(value), node.Value.Line)
+			node.Children = make([]*ast.Node, 0)
+		}
+		return nil
+	}
 
+	switch node.Value.Type {
+	case token.PLUS, token.MINUS, token.AND, token.OR, token.XOR, token.EQEQ, token.NOTEQ,
+		token.LT, token.LTEQ, token.GT, token.GTEQ:
+		if len(node.Children) == 2 {
+			return analyzer.foldOperands(node, nil)
+		}
+	case token.QUESTION:
+		const CONDITION = 0
+		const BRANCHES = 1
+		err := analyzer.foldConstant(node.Children[CONDITION], nil)
+		if err != nil {
+			return err
+		}
+		//the value of a branch is the value of the expression, so it's saved in the target too
+		return analyzer.foldOperands(node.Children[BRANCHES], target)
+	case token.RPAREN:
+		callee := node.Children[0]
+		if len(node.Children) == 1 && callee.Value.Type != token.IDENT {
+			return analyzer.foldConstant(callee, target)
+		}
+		symbol, isACall := scope.Symbols[callee.Value.Literal]
+		if isACall && symbol.IsFunction && len(node.Children) == 2 {
+			args := symbol.DataType.(symboltable.Function).Args
+			return analyzer.foldList(node.Children[1], func(i int) interface{} {
+				return args[i]
+			})
+		}
+	case token.RBRACE:
+		array, isAnArray := target.(symboltable.Array)
+		return analyzer.foldList(node.Children[0], func(int) interface{} {
+			if isAnArray {
+				return array.Of
 			}
+			return nil
+		})
+	}
+	for _, child := range node.Children {
+		err := analyzer.foldConstant(child, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//foldOperands folds the constants of both operands of the operation led by node, each of them saved in the data type
+//of the other one, so a constant operated with a word is computed as a word. If target is a word, both are saved in it
+func (analyzer *SemanticAnalyzer) foldOperands(node *ast.Node, target interface{}) error {
+	left, err := analyzer.dataTypeOf(node.Children[0])
+	if err != nil {
+		return err
+	}
+	right, err := analyzer.dataTypeOf(node.Children[1])
+	if err != nil {
+		return err
+	}
+	if symboltable.IsWord(target) {
+		left, right = target, target
+	}
+	err = analyzer.foldConstant(node.Children[0], right)
+	if err != nil {
+		return err
+	}
+	return analyzer.foldConstant(node.Children[1], left)
+}
+
+//foldList folds the constants of the elements of a list separated by commas, like the params of a call,
+//each of them saved in the data type given by target for its position
+func (analyzer *SemanticAnalyzer) foldList(list *ast.Node, target func(int) interface{}) error {
+	element := list
+	i := 0
+	for element.Value.Type == token.COMMA {
+		err := analyzer.foldConstant(element.Children[0], target(i))
+		if err != nil {
+			return err
 		}
+		element = element.Children[1]
+		i++
 	}
-	return symboltable.NewVoid(), nil
+	return analyzer.foldConstant(element, target(i))
+}
+
+//dataTypeOf returns the data type of the expression led by node in the scope of the datatypeFactory
+func (analyzer *SemanticAnalyzer) dataTypeOf(node *ast.Node) (interface{}, error) {
+	analyzer.datatypeFactory.SetCxtNode(node)
+	return analyzer.datatypeFactory.GetDataType()
 }
 
 //handleParams validates the semantic of all the params of a function and save them in the symbol table of a new scope
@@ -419,18 +1152,17 @@ func (analyzer *SemanticAnalyzer) handleParams() ([]interface{}, error) {
 				return nil, err
 			}
 
-			totalSize += symboltable.GetSize(param)
+			totalSize += symboltable.GetParamSize(param)
 			args = append(args, param)
 			analyzer.ctxNode = backup
 			analyzer.ctxNode = analyzer.ctxNode.Children[1]
 
 			if analyzer.ctxNode.Value.Type != token.COMMA {
 				param, err = analyzer.handleParam()
-				totalSize += symboltable.GetSize(param)
-
 				if err != nil {
 					return nil, err
 				}
+				totalSize += symboltable.GetParamSize(param)
 
 				args = append(args, param)
 			}
@@ -438,11 +1170,10 @@ func (analyzer *SemanticAnalyzer) handleParams() ([]interface{}, error) {
 		}
 	} else {
 		param, err := analyzer.handleParam()
-		totalSize += symboltable.GetSize(param)
-
 		if err != nil {
 			return nil, err
 		}
+		totalSize += symboltable.GetParamSize(param)
 
 		args = append(args, param)
 	}
@@ -465,7 +1196,7 @@ func (analyzer *SemanticAnalyzer) handleParam() (interface{}, error) {
 	name := analyzer.ctxNode.Children[IDENT].Value.Literal
 	datatypeTree := analyzer.ctxNode.Children[DATATYPE]
 	analyzer.updateDataTypeFactoryCtx(datatypeTree)
-	datatype, err := analyzer.datatypeFactory.GetDataType()
+	datatype, err := analyzer.datatypeFactory.GetDeclaredDataType()
 	if err != nil {
 		return nil, err
 	}
@@ -476,8 +1207,8 @@ func (analyzer *SemanticAnalyzer) handleParam() (interface{}, error) {
 		return nil, err
 	}
 
-	switch datatype.(type) {
-	case symboltable.Array:
+	//arrays are passed as a pointer to their first element, but structs can't be passed
+	if symboltable.IsAStruct(datatype) {
 		line := analyzer.ctxNode.Value.Line
 		err := errors.New(errorhandler.InvalidParamType(line, symboltable.Fmt(datatype)))
 		return nil, err
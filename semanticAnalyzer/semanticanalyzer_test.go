@@ -1,7 +1,9 @@
 package semanticAnalyzer
 
 import (
+	"errors"
 	"github.com/NoetherianRing/c8-compiler/ast"
+	"github.com/NoetherianRing/c8-compiler/errorhandler"
 	"github.com/NoetherianRing/c8-compiler/lexer"
 	"github.com/NoetherianRing/c8-compiler/syntacticanalyzer"
 	"github.com/NoetherianRing/c8-compiler/token"
@@ -17,7 +19,7 @@ func TestStart(t *testing.T) {
 		testPath    string
 		err         error
 	}
-	const numberOfValidTests = 6
+	const numberOfValidTests = 12
 	testCases := make([]cases, 0)
 	for i := 0; i < numberOfValidTests; i++ {
 		path := "../fixtures/semantic/valid/valid_test" + strconv.Itoa(i) + ".text"
@@ -32,6 +34,56 @@ func TestStart(t *testing.T) {
 		})
 
 	}
+	//the error expected from each invalid test, in order
+	invalidTests := []error{
+		errors.New(errorhandler.UnreachableCode(3)),
+		errors.New(errorhandler.MissingReturn(1, "f")),
+		errors.New(errorhandler.LoopControlOutsideLoop(4, "break")),
+		errors.New(errorhandler.UnresolvedLabel(5, "inner")),
+		errors.New(errorhandler.DivisionByZero(1)),
+		errors.New(errorhandler.ConstantOverflow(1, "byte")),
+		errors.New(errorhandler.ConstantModification(3, "A")),
+		errors.New(errorhandler.ConstantOverflow(3, "byte")),
+		errors.New(errorhandler.NotConstantIndex(4)),
+		errors.New(errorhandler.InvalidSpriteRow(1, "#########")),
+		errors.New(errorhandler.InvalidSpriteHeight(2, 16)),
+		errors.New(errorhandler.EmbedSizeMismatch(1, "assets/bytes.bin", 8, "[3][4]byte")),
+		errors.New(errorhandler.EmbedNeedsByteArray(1, "assets/bytes.bin", "[4]bool")),
+		errors.New(errorhandler.DuplicateCase(7, 2)),
+		errors.New(errorhandler.MultipleDefaults(10)),
+		errors.New(errorhandler.DataTypesMismatch(3, "byte", token.COLON, "bool")),
+		errors.New(errorhandler.UnresolvedField(4, "name", "T")),
+		errors.New(errorhandler.InvalidComparison(5, "T")),
+		errors.New(errorhandler.StructContainsItself(3, "N")),
+		errors.New(errorhandler.ByteOutOfRange(1, 511)),
+		errors.New(errorhandler.ConstantOverflow(3, "byte")),
+		errors.New(errorhandler.DataTypesMismatch(4, "int8", token.LT, "byte")),
+		errors.New(errorhandler.DataTypesMismatch(4, "int8", token.PLUS, "byte")),
+		errors.New(errorhandler.DataTypesMismatch(4, "int8", token.EQEQ, "byte")),
+		errors.New(errorhandler.ConstantOverflow(2, "int8")),
+		errors.New(errorhandler.ConstantOverflow(1, "int8")),
+		errors.New(errorhandler.UndefinedOperator(3, token.ASTERISK, "int8")),
+		errors.New(errorhandler.InvalidConversion(3, "[2]byte", "byte")),
+		errors.New(errorhandler.DataTypesMismatch(5, "Dir", token.EQEQ, "State")),
+		errors.New(errorhandler.DataTypesMismatch(3, "Dir", token.EQ, "byte")),
+		errors.New(errorhandler.NotAnArrayVariable(5, "[2]byte")),
+		errors.New(errorhandler.LenOfNonArray(3, "byte")),
+		errors.New(errorhandler.DataTypesMismatch(6, "[2]byte", token.EQ, "[3]byte")),
+		errors.New(errorhandler.InvalidReturnType(2, "T")),
+		errors.New(errorhandler.DataTypesMismatch(2, "word", token.EQ, "bool")),
+	}
+	for i, expected := range invalidTests {
+		path := "../fixtures/semantic/invalid/invalid_test" + strconv.Itoa(i) + ".text"
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			assert.Error(t, err)
+		}
+		testCases = append(testCases, cases{
+			description: "invalid_test" + strconv.Itoa(i),
+			testPath:    absPath,
+			err:         expected,
+		})
+	}
 	grammar := syntacticanalyzer.GetGrammar()
 	program := grammar[syntacticanalyzer.GetStartSymbol()]
 	for _, scenario := range testCases {
@@ -45,8 +97,9 @@ func TestStart(t *testing.T) {
 		valid := program.Build(&tokens, tree)
 		assert.True(t, valid, "invalid syntax")
 		semantic := NewSemanticAnalyzer(tree)
+		semantic.SetSourceDir(filepath.Dir(scenario.testPath))
 		_, err = semantic.Start()
-		assert.NoError(t, err)
+		assert.Equal(t, scenario.err, err, scenario.description)
 
 	}
 
@@ -12,6 +12,8 @@ const (
 	KindByte = iota
 	KindVoid
 	KindBool
+	KindWord
+	KindInt8
 )
 const (
 	FunctionClean        = "clean"
@@ -45,6 +47,22 @@ type Pointer struct {
 	PointsTo interface{}
 }
 
+type Struct struct {
+	Name   string
+	Fields []Field
+}
+
+//Enum is a byte whose values are named. Enums are only equal to themselves
+type Enum struct {
+	Name string
+}
+
+type Field struct {
+	Identifier string
+	DataType   interface{}
+	Offset     int //the position of the field from the start of the struct, in bytes
+}
+
 type Function struct {
 	Return interface{}
 	Args   []interface{}
@@ -53,6 +71,10 @@ type Function struct {
 type Symbol struct {
 	Identifier string
 	IsFunction bool
+	IsConstant bool
+	IsType     bool   //true if the identifier names a struct type instead of a variable
+	Value      int    //the value of a constant, which is known at compile time
+	Data       []byte //the value of a global variable embedded from a file
 	DataType   interface{}
 }
 
@@ -65,6 +87,8 @@ func (array Array) SizeOfElements() int {
 	case Array:
 		of := array.Of.(Array)
 		return of.SizeOfElements() * of.Length
+	case Struct, Enum:
+		return GetSize(array.Of)
 	default:
 		return 0
 	}
@@ -106,6 +130,34 @@ func (pointer Pointer) Compare(datatype interface{}) bool {
 	return Compare(pointer.PointsTo, toCompare.PointsTo)
 }
 
+//Compare checks if two structs are the same type. Structs are only equal to themselves, so their names are compared
+func (structure Struct) Compare(datatype interface{}) bool {
+	toCompare, ok := datatype.(Struct)
+	if !ok {
+		return false
+	}
+	return toCompare.Name == structure.Name
+}
+
+//Compare checks if two enums are the same type, by comparing their names
+func (enum Enum) Compare(datatype interface{}) bool {
+	toCompare, ok := datatype.(Enum)
+	if !ok {
+		return false
+	}
+	return toCompare.Name == enum.Name
+}
+
+//Field returns the field of the struct with the given identifier, and false if the struct doesn't have it
+func (structure Struct) Field(identifier string) (Field, bool) {
+	for _, field := range structure.Fields {
+		if field.Identifier == identifier {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
 func Compare(dataType1 interface{}, dataType2 interface{}) bool {
 	switch dataType1.(type) {
 	case Pointer:
@@ -114,6 +166,10 @@ func Compare(dataType1 interface{}, dataType2 interface{}) bool {
 		return dataType1.(Array).Compare(dataType2)
 	case Simple:
 		return dataType1.(Simple).Compare(dataType2)
+	case Struct:
+		return dataType1.(Struct).Compare(dataType2)
+	case Enum:
+		return dataType1.(Enum).Compare(dataType2)
 
 	default:
 		panic(errorhandler.UnexpectedCompilerError())
@@ -132,12 +188,21 @@ func Fmt(datatype interface{}) string {
 		if simpleDataType.Kind == KindByte {
 			return "byte"
 		} else {
-			if simpleDataType.Kind == KindBool {
+			switch simpleDataType.Kind {
+			case KindBool:
 				return "bool"
-			} else {
+			case KindWord:
+				return "word"
+			case KindInt8:
+				return "int8"
+			default:
 				return "void"
 			}
 		}
+	case Struct:
+		return datatype.(Struct).Name
+	case Enum:
+		return datatype.(Enum).Name
 
 	default:
 		panic(errorhandler.UnexpectedCompilerError())
@@ -155,6 +220,33 @@ func NewArray(length int, datatype interface{}) Array {
 	return Array{Length: length, Of: datatype}
 }
 
+//NewStruct returns a struct whose fields are laid out one after the other, in the order they are declared
+func NewStruct(name string, identifiers []string, datatypes []interface{}) Struct {
+	fields := make([]Field, len(identifiers))
+	offset := 0
+	for i, identifier := range identifiers {
+		fields[i] = Field{Identifier: identifier, DataType: datatypes[i], Offset: offset}
+		offset += GetSize(datatypes[i])
+	}
+	return Struct{Name: name, Fields: fields}
+}
+
+//NewEmptyStruct returns a struct with room for a number of fields, which are laid out by SetFields. It allows a struct
+//to be saved before its fields are known, so one of them can point to it
+func NewEmptyStruct(name string, numberOfFields int) Struct {
+	return Struct{Name: name, Fields: make([]Field, numberOfFields)}
+}
+
+//SetFields lays out the fields of a struct returned by NewEmptyStruct. The fields are shared by all the copies of the struct,
+//including the ones its fields point to
+func (structure Struct) SetFields(identifiers []string, datatypes []interface{}) {
+	copy(structure.Fields, NewStruct(structure.Name, identifiers, datatypes).Fields)
+}
+
+func NewEnum(name string) Enum {
+	return Enum{Name: name}
+}
+
 func NewBool() Simple {
 	return Simple{Size: 1, Kind: KindBool}
 }
@@ -163,6 +255,16 @@ func NewByte() Simple {
 	return Simple{Size: 1, Kind: KindByte}
 }
 
+//NewWord returns a 16 bits unsigned integer, stored in two bytes with the high bits first
+func NewWord() Simple {
+	return Simple{Size: 2, Kind: KindWord}
+}
+
+//NewInt8 returns a signed byte, stored in two's complement
+func NewInt8() Simple {
+	return Simple{Size: 1, Kind: KindInt8}
+}
+
 func NewVoid() Simple {
 	return Simple{Size: 0, Kind: KindVoid}
 }
@@ -218,6 +320,27 @@ func (scope *Scope) AddSymbol(identifier string, datatype interface{}) bool {
 	return true
 }
 
+//AddType saves a struct or an enum type in the symbol table, it returns false if the identifier is already in use
+func (scope *Scope) AddType(identifier string, datatype interface{}) bool {
+	ok := scope.AddSymbol(identifier, datatype)
+	if !ok {
+		return false
+	}
+	scope.Symbols[identifier].IsType = true
+	return true
+}
+
+//AddConstant saves a constant and its value in the symbol table, it returns false if the identifier is already in use
+func (scope *Scope) AddConstant(identifier string, datatype interface{}, value int) bool {
+	ok := scope.AddSymbol(identifier, datatype)
+	if !ok {
+		return false
+	}
+	scope.Symbols[identifier].IsConstant = true
+	scope.Symbols[identifier].Value = value
+	return true
+}
+
 func GetSize(datatype interface{}) int {
 	switch datatype.(type) {
 	case Pointer:
@@ -226,12 +349,41 @@ func GetSize(datatype interface{}) int {
 		return datatype.(Array).SizeOfElements() * datatype.(Array).Length
 	case Simple:
 		return datatype.(Simple).Size
+	case Struct:
+		size := 0
+		for _, field := range datatype.(Struct).Fields {
+			size += GetSize(field.DataType)
+		}
+		return size
+	case Enum:
+		return 1
 	default:
 		panic(errorhandler.UnexpectedCompilerError())
 	}
 
 }
 
+//GetParamSize returns the size a param takes in registers and in the frame of a function. Arrays are passed as
+//a pointer to their first element, so they take the size of a pointer
+func GetParamSize(datatype interface{}) int {
+	array, isAnArray := datatype.(Array)
+	if isAnArray {
+		return GetSize(NewPointer(array.Of))
+	}
+	return GetSize(datatype)
+}
+
+func IsAnEnum(datatype interface{}) bool {
+	switch datatype.(type) {
+	case Enum:
+		return true
+
+	default:
+		return false
+	}
+
+}
+
 func IsAnArray(datatype interface{}) bool {
 	switch datatype.(type) {
 	case Array:
@@ -243,17 +395,90 @@ func IsAnArray(datatype interface{}) bool {
 
 }
 
+func IsAStruct(datatype interface{}) bool {
+	switch datatype.(type) {
+	case Struct:
+		return true
+
+	default:
+		return false
+	}
+
+}
+
 func IsNumeric(datatype interface{}) bool {
 	switch datatype.(type) {
 	case Pointer:
 		return true
 	case Simple:
-		return IsByte(datatype)
+		return IsInteger(datatype)
+	default:
+		return false
+	}
+
+}
+
+//IsInteger returns true if the datatype is a byte, a word or an int8
+func IsInteger(datatype interface{}) bool {
+	return IsUnsigned(datatype) || IsInt8(datatype)
+}
+
+//IsUnsigned returns true if the datatype is a byte or a word
+func IsUnsigned(datatype interface{}) bool {
+	return IsByte(datatype) || IsWord(datatype)
+}
+
+func IsInt8(datatype interface{}) bool {
+	switch datatype.(type) {
+	case Simple:
+		return datatype.(Simple).Kind == KindInt8
+	default:
+		return false
+	}
+
+}
+
+func IsWord(datatype interface{}) bool {
+	switch datatype.(type) {
+	case Simple:
+		return datatype.(Simple).Kind == KindWord
 	default:
 		return false
 	}
 
 }
+
+//IsAssignable checks if a value of the datatype from can be saved in a variable of the datatype to.
+//It is true when both are the same datatype, or when a byte is widened to a word
+func IsAssignable(to interface{}, from interface{}) bool {
+	if IsWord(to) && IsByte(from) {
+		return true
+	}
+	toArray, ok := to.(Array)
+	if ok {
+		fromArray, ok := from.(Array)
+		if !ok || fromArray.Length != toArray.Length {
+			return false
+		}
+		return IsAssignable(toArray.Of, fromArray.Of)
+	}
+	return Compare(to, from)
+}
+
+//Widen returns the datatype in which an operation between two integers is made, which is a word if any of them is a word.
+//It returns false if the datatypes can't be mixed, signed and unsigned integers can't be mixed without a conversion
+func Widen(dataType1 interface{}, dataType2 interface{}) (interface{}, bool) {
+	if IsUnsigned(dataType1) && IsUnsigned(dataType2) {
+		if IsWord(dataType1) || IsWord(dataType2) {
+			return NewWord(), true
+		}
+		return NewByte(), true
+	}
+	if Compare(dataType1, dataType2) {
+		return dataType1, true
+	}
+	return nil, false
+}
 func IsByte(datatype interface{}) bool {
 	switch datatype.(type) {
 	case Simple:
@@ -35,13 +35,41 @@ The different options of bodies in each production are separated by the symbol |
 const PROGRAM = "program"
 const BLOCK = "block"
 const FUNC_BLOCK = "funcblock"
-const FUNC_STATEMENTS = "funcstatements"
 const STATEMENTS = "statements"
 const STATEMENT = "statement"
 const RETURN_STATEMENT = "returnstatement"
+const WHILE_STATEMENT = "whilestatement"
+const FOR_STATEMENT = "forstatement"
+const FOR_INIT = "forinit"
+const FOR_STEP = "forstep"
+const SWITCH_BLOCK = "switchblock"
+const CASE_CLAUSES = "caseclauses"
+const CASE_CLAUSE = "caseclause"
+const CASE_VALUES = "casevalues"
+const STRUCT_TYPE = "structtype"
+const FIELDS = "fields"
+const FIELD = "field"
+const ENUM_TYPE = "enumtype"
+const ENUM_VALUES = "enumvalues"
+const ENUM_VALUE = "enumvalue"
+const LOOP_STATEMENT = "loopstatement"
+const LOOP_CONTROL = "loopcontrol"
 const DECLARATION = "declaration"
+const INITIALIZATION = "initialization"
+const ARRAY_LITERAL = "arrayliteral"
+const ELEMENTS = "elements"
+const ELEMENT = "element"
+const SPRITE_LITERAL = "spriteliteral"
+const SPRITE_BLOCK = "spriteblock"
+const SPRITE_ROWS = "spriterows"
+const EMBED_LITERAL = "embedliteral"
+const EMBED_ARG = "embedarg"
+const FILE_PATH = "filepath"
+const SIZE_OPERATOR = "sizeoperator"
+const SIZE_ARG = "sizearg"
 const PARAM_DECLARATION = "paramdeclaration"
 const VAR = "var"
+const VAR_PATH = "varpath"
 const LITERAL = "literal"
 const ADDRESS = "address"
 const IDENT = "ident"
@@ -53,6 +81,8 @@ const FUNC_DATATYPE = "funcdatatype"
 const NEW_LINE = "newline"
 
 const EXPRESSION = "expression"
+const CONDITIONAL_BRANCHES = "conditionalbranches"
+const EXPRESSION_P11 = "expression_p11"
 const EXPRESSION_P10 = "expression_p10"
 const EXPRESSION_P9 = "expression_p9"
 const EXPRESSION_P8 = "expression_p8"
@@ -65,6 +95,10 @@ const EXPRESSION_P2 = "expression_p2"
 const EXPRESSION_P1 = "expression_p1"
 const EXPRESSION_P0 = "expression_p0"
 
+//terminals that match any compound assignment (+=, -=, ...), and an increment or a decrement
+const COMPOUND_ASSIGNMENT = "compoundassignment"
+const INCREMENT = "increment"
+
 type cache struct {
 	symbol string
 	src    *[]token.Token
@@ -140,8 +174,8 @@ func (t Terminal) Build(src *[]token.Token, tree *ast.SyntaxTree) bool {
 	//Log.printLog()
 	//fmt.Printf("SOURCE: %s WAITING: %s EQUAL: %t line: %d\n", (*src)[0].Literal, token.Type(t), (*src)[0].Type == token.Type(t), (*src)[0].Line)
 	if t.Equals((*src)[0].Type) {
-		//New lines don't have a purpose in our tree, so we skip them
-		if token.Type(t) != token.NEWLINE {
+		//New lines and semicolons don't have a purpose in our tree, so we skip them
+		if token.Type(t) != token.NEWLINE && token.Type(t) != token.SEMICOLON {
 			tree.Head.Value = (*src)[0]
 		}
 		*src = (*src)[1:]
@@ -156,7 +190,7 @@ func (t Terminal) GetValue() string {
 
 func (t Terminal) Equals(t2 token.Type) bool {
 	switch t {
-	case EXPRESSION:
+	case EXPRESSION_P11:
 		return t2 == token.LOR
 	case EXPRESSION_P10:
 		return t2 == token.LAND
@@ -177,6 +211,11 @@ func (t Terminal) Equals(t2 token.Type) bool {
 		return t2 == token.PLUS || t2 == token.MINUS
 	case EXPRESSION_P2:
 		return t2 == token.ASTERISK || t2 == token.PERCENT || t2 == token.SLASH
+	case COMPOUND_ASSIGNMENT:
+		_, isCompound := token.LookupCompoundAssignment(t2)
+		return isCompound && t2 != token.PLUSPLUS && t2 != token.MINUSMINUS
+	case INCREMENT:
+		return t2 == token.PLUSPLUS || t2 == token.MINUSMINUS
 	default:
 		return token.Type(t) == t2
 	}
@@ -312,12 +351,40 @@ func GetGrammar() map[string]*NonTerminal {
 	productions[BLOCK] = new(NonTerminal)
 	productions[FUNC_BLOCK] = new(NonTerminal)
 	productions[STATEMENTS] = new(NonTerminal)
-	productions[FUNC_STATEMENTS] = new(NonTerminal)
 	productions[STATEMENT] = new(NonTerminal)
 	productions[RETURN_STATEMENT] = new(NonTerminal)
+	productions[WHILE_STATEMENT] = new(NonTerminal)
+	productions[FOR_STATEMENT] = new(NonTerminal)
+	productions[FOR_INIT] = new(NonTerminal)
+	productions[FOR_STEP] = new(NonTerminal)
+	productions[SWITCH_BLOCK] = new(NonTerminal)
+	productions[CASE_CLAUSES] = new(NonTerminal)
+	productions[CASE_CLAUSE] = new(NonTerminal)
+	productions[CASE_VALUES] = new(NonTerminal)
+	productions[STRUCT_TYPE] = new(NonTerminal)
+	productions[FIELDS] = new(NonTerminal)
+	productions[FIELD] = new(NonTerminal)
+	productions[ENUM_TYPE] = new(NonTerminal)
+	productions[ENUM_VALUES] = new(NonTerminal)
+	productions[ENUM_VALUE] = new(NonTerminal)
+	productions[LOOP_STATEMENT] = new(NonTerminal)
+	productions[LOOP_CONTROL] = new(NonTerminal)
 	productions[DECLARATION] = new(NonTerminal)
+	productions[INITIALIZATION] = new(NonTerminal)
+	productions[ARRAY_LITERAL] = new(NonTerminal)
+	productions[ELEMENTS] = new(NonTerminal)
+	productions[ELEMENT] = new(NonTerminal)
+	productions[SPRITE_LITERAL] = new(NonTerminal)
+	productions[SPRITE_BLOCK] = new(NonTerminal)
+	productions[SPRITE_ROWS] = new(NonTerminal)
+	productions[EMBED_LITERAL] = new(NonTerminal)
+	productions[EMBED_ARG] = new(NonTerminal)
+	productions[FILE_PATH] = new(NonTerminal)
+	productions[SIZE_OPERATOR] = new(NonTerminal)
+	productions[SIZE_ARG] = new(NonTerminal)
 	productions[PARAM_DECLARATION] = new(NonTerminal)
 	productions[VAR] = new(NonTerminal)
+	productions[VAR_PATH] = new(NonTerminal)
 	productions[LITERAL] = new(NonTerminal)
 	productions[ADDRESS] = new(NonTerminal)
 	productions[IDENT] = new(NonTerminal)
@@ -329,6 +396,8 @@ func GetGrammar() map[string]*NonTerminal {
 	productions[NEW_LINE] = new(NonTerminal)
 
 	productions[EXPRESSION] = new(NonTerminal)
+	productions[CONDITIONAL_BRANCHES] = new(NonTerminal)
+	productions[EXPRESSION_P11] = new(NonTerminal)
 	productions[EXPRESSION_P10] = new(NonTerminal)
 	productions[EXPRESSION_P9] = new(NonTerminal)
 	productions[EXPRESSION_P8] = new(NonTerminal)
@@ -372,7 +441,7 @@ func GetGrammar() map[string]*NonTerminal {
 
 	grammarSymbols = make([]GrammarSymbol, 0)
 	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
-	grammarSymbols = append(grammarSymbols, productions[FUNC_STATEMENTS])
+	grammarSymbols = append(grammarSymbols, productions[STATEMENTS])
 	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
 	options[0].grammarSymbols = grammarSymbols
 
@@ -394,21 +463,6 @@ func GetGrammar() map[string]*NonTerminal {
 	productions[STATEMENTS].options = options
 	productions[STATEMENTS].head = STATEMENTS
 
-	//FUNC_STATEMENTS:
-	options = make([]Option, 2)
-
-	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[STATEMENT])
-	grammarSymbols = append(grammarSymbols, productions[FUNC_STATEMENTS])
-	options[0].grammarSymbols = grammarSymbols
-
-	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[RETURN_STATEMENT])
-	options[1].grammarSymbols = grammarSymbols
-
-	productions[FUNC_STATEMENTS].options = options
-	productions[FUNC_STATEMENTS].head = FUNC_STATEMENTS
-
 	//RETURN_STATEMENT:
 	options = make([]Option, 2)
 
@@ -428,281 +482,994 @@ func GetGrammar() map[string]*NonTerminal {
 	productions[RETURN_STATEMENT].options = options
 	productions[RETURN_STATEMENT].head = RETURN_STATEMENT
 
-	//STATEMENT
-	options = make([]Option, 8)
+	//WHILE_STATEMENT:
+	options = make([]Option, 1)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.WHILE))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, productions[BLOCK])
+
 	options[0].grammarSymbols = grammarSymbols
 
+	productions[WHILE_STATEMENT].options = options
+	productions[WHILE_STATEMENT].head = WHILE_STATEMENT
+
+	//FOR_STATEMENT: "for" has four children, the initialization, the condition, the step and the block
+	options = make([]Option, 1)
+
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[DECLARATION])
-	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.FOR))
+	grammarSymbols = append(grammarSymbols, productions[FOR_INIT])
+	grammarSymbols = append(grammarSymbols, Terminal(token.SEMICOLON))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, Terminal(token.SEMICOLON))
+	grammarSymbols = append(grammarSymbols, productions[FOR_STEP])
+	grammarSymbols = append(grammarSymbols, productions[BLOCK])
 
-	options[1].grammarSymbols = grammarSymbols
+	options[0].grammarSymbols = grammarSymbols
+
+	productions[FOR_STATEMENT].options = options
+	productions[FOR_STATEMENT].head = FOR_STATEMENT
+
+	//FOR_INIT: a declaration with an initialization, or an assignation
+	options = make([]Option, 3)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.FUNCTION))
+	grammarSymbols = append(grammarSymbols, Terminal(token.LET))
 	grammarSymbols = append(grammarSymbols, productions[IDENT])
-	grammarSymbols = append(grammarSymbols, productions[ARGS])
-	grammarSymbols = append(grammarSymbols, productions[FUNC_DATATYPE])
-	grammarSymbols = append(grammarSymbols, productions[FUNC_BLOCK])
-	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	grammarSymbols = append(grammarSymbols, productions[INITIALIZATION])
 
-	options[2].grammarSymbols = grammarSymbols
+	options[0].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[CALL])
-	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.LET))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, productions[INITIALIZATION])
 
-	options[3].grammarSymbols = grammarSymbols
+	options[1].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
 	grammarSymbols = append(grammarSymbols, productions[VAR])
 	grammarSymbols = append(grammarSymbols, Terminal(token.EQ))
 	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
-	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
 
-	options[4].grammarSymbols = grammarSymbols
+	options[2].grammarSymbols = grammarSymbols
+
+	productions[FOR_INIT].options = options
+	productions[FOR_INIT].head = FOR_INIT
+
+	//FOR_STEP: an assignation, a compound assignation, an increment or a decrement
+	options = make([]Option, 3)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.WHILE))
+	grammarSymbols = append(grammarSymbols, productions[VAR])
+	grammarSymbols = append(grammarSymbols, Terminal(token.EQ))
 	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
-	grammarSymbols = append(grammarSymbols, productions[BLOCK])
-	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
 
-	options[5].grammarSymbols = grammarSymbols
+	options[0].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.IF))
+	grammarSymbols = append(grammarSymbols, productions[VAR])
+	grammarSymbols = append(grammarSymbols, Terminal(COMPOUND_ASSIGNMENT))
 	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
-	grammarSymbols = append(grammarSymbols, productions[BLOCK])
-	grammarSymbols = append(grammarSymbols, Terminal(token.ELSE))
-	grammarSymbols = append(grammarSymbols, productions[BLOCK])
-	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
 
-	options[6].grammarSymbols = grammarSymbols
+	options[1].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.IF))
-	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
-	grammarSymbols = append(grammarSymbols, productions[BLOCK])
-	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[VAR])
+	grammarSymbols = append(grammarSymbols, Terminal(INCREMENT))
 
-	options[7].grammarSymbols = grammarSymbols
+	options[2].grammarSymbols = grammarSymbols
 
-	productions[STATEMENT].options = options
-	productions[STATEMENT].head = STATEMENT
+	productions[FOR_STEP].options = options
+	productions[FOR_STEP].head = FOR_STEP
 
-	//DECLARATION
+	//SWITCH_BLOCK: the clauses are the children of "}"
 	options = make([]Option, 1)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.LET))
-	grammarSymbols = append(grammarSymbols, productions[IDENT])
-	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[CASE_CLAUSES])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
+
 	options[0].grammarSymbols = grammarSymbols
 
-	productions[DECLARATION].options = options
-	productions[DECLARATION].head = DECLARATION
+	productions[SWITCH_BLOCK].options = options
+	productions[SWITCH_BLOCK].head = SWITCH_BLOCK
+
+	//CASE_CLAUSES:
+	options = make([]Option, 2)
 
-	// IDENT
-	options = make([]Option, 1)
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.IDENT))
+	grammarSymbols = append(grammarSymbols, productions[CASE_CLAUSE])
+	grammarSymbols = append(grammarSymbols, productions[CASE_CLAUSES])
+
 	options[0].grammarSymbols = grammarSymbols
 
-	productions[IDENT].options = options
-	productions[IDENT].head = IDENT
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[CASE_CLAUSE])
 
-	// CALL
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[CASE_CLAUSES].options = options
+	productions[CASE_CLAUSES].head = CASE_CLAUSES
+
+	//CASE_CLAUSE: "case" has two children, its values and its block, and "default" only has the block
 	options = make([]Option, 2)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[IDENT])
-	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
-	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	grammarSymbols = append(grammarSymbols, Terminal(token.CASE))
+	grammarSymbols = append(grammarSymbols, productions[CASE_VALUES])
+	grammarSymbols = append(grammarSymbols, productions[BLOCK])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
 	options[0].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[IDENT])
-	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
-	grammarSymbols = append(grammarSymbols, productions[PARAMS])
-	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	grammarSymbols = append(grammarSymbols, Terminal(token.DEFAULT))
+	grammarSymbols = append(grammarSymbols, productions[BLOCK])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
 	options[1].grammarSymbols = grammarSymbols
 
-	productions[CALL].options = options
-	productions[CALL].head = CALL
+	productions[CASE_CLAUSE].options = options
+	productions[CASE_CLAUSE].head = CASE_CLAUSE
 
-	//PARAM DECLARATION
+	//CASE_VALUES: the values are chained by commas, as the elements of an array literal
 	options = make([]Option, 2)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[DECLARATION])
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
 	grammarSymbols = append(grammarSymbols, Terminal(token.COMMA))
-	grammarSymbols = append(grammarSymbols, productions[PARAM_DECLARATION])
+	grammarSymbols = append(grammarSymbols, productions[CASE_VALUES])
+
 	options[0].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[DECLARATION])
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+
 	options[1].grammarSymbols = grammarSymbols
 
-	productions[PARAM_DECLARATION].options = options
-	productions[PARAM_DECLARATION].head = PARAM_DECLARATION
-	//PARAMS
+	productions[CASE_VALUES].options = options
+	productions[CASE_VALUES].head = CASE_VALUES
+
+	//STRUCT_TYPE: the fields are the children of "}"
 	options = make([]Option, 2)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
-	grammarSymbols = append(grammarSymbols, Terminal(token.COMMA))
-	grammarSymbols = append(grammarSymbols, productions[PARAMS])
+	grammarSymbols = append(grammarSymbols, Terminal(token.STRUCT))
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[FIELDS])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
+
 	options[0].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, Terminal(token.STRUCT))
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[FIELDS])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
+
 	options[1].grammarSymbols = grammarSymbols
 
-	productions[PARAMS].options = options
-	productions[PARAMS].head = PARAMS
+	productions[STRUCT_TYPE].options = options
+	productions[STRUCT_TYPE].head = STRUCT_TYPE
 
-	// DATATYPE
-	options = make([]Option, 4)
+	//FIELDS: the fields are separated by semicolons or new lines
+	options = make([]Option, 5)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.ASTERISK))
-	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	grammarSymbols = append(grammarSymbols, productions[FIELD])
+	grammarSymbols = append(grammarSymbols, Terminal(token.SEMICOLON))
+	grammarSymbols = append(grammarSymbols, productions[FIELDS])
+
 	options[0].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACKET))
-	grammarSymbols = append(grammarSymbols, productions[LITERAL])
-	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACKET))
-	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	grammarSymbols = append(grammarSymbols, productions[FIELD])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[FIELDS])
+
 	options[1].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.TYPEBOOL))
+	grammarSymbols = append(grammarSymbols, productions[FIELD])
+	grammarSymbols = append(grammarSymbols, Terminal(token.SEMICOLON))
+
 	options[2].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.TYPEBYTE))
+	grammarSymbols = append(grammarSymbols, productions[FIELD])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
 	options[3].grammarSymbols = grammarSymbols
 
-	productions[DATATYPE].options = options
-	productions[DATATYPE].head = DATATYPE
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[FIELD])
 
-	//FUNC DATA TYPE:
-	options = make([]Option, 2)
+	options[4].grammarSymbols = grammarSymbols
 
-	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.VOID))
-	options[0].grammarSymbols = grammarSymbols
+	productions[FIELDS].options = options
+	productions[FIELDS].head = FIELDS
+
+	//FIELD: a field doesn't have a terminal to lead it, so its identifier and its data type are added
+	//one after the other to the children of "}"
+	options = make([]Option, 1)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
 	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
-	options[1].grammarSymbols = grammarSymbols
 
-	productions[FUNC_DATATYPE].options = options
-	productions[FUNC_DATATYPE].head = FUNC_DATATYPE
+	options[0].grammarSymbols = grammarSymbols
 
-	//VAR:
-	options = make([]Option, 6)
+	productions[FIELD].options = options
+	productions[FIELD].head = FIELD
 
-	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.ASTERISK))
-	grammarSymbols = append(grammarSymbols, productions[VAR])
+	//ENUM_TYPE: the values are the child of "}", and they can be written in several lines
+	options = make([]Option, 4)
 
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[ENUM_VALUES])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
 	options[0].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACKET))
-	grammarSymbols = append(grammarSymbols, productions[LITERAL])
-	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACKET))
-	grammarSymbols = append(grammarSymbols, productions[VAR])
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[ENUM_VALUES])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
 	options[1].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACKET))
-	grammarSymbols = append(grammarSymbols, productions[IDENT])
-	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACKET))
-	grammarSymbols = append(grammarSymbols, productions[VAR])
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[ENUM_VALUES])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
 	options[2].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[ADDRESS])
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[ENUM_VALUES])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
 	options[3].grammarSymbols = grammarSymbols
 
-	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[CALL])
-	options[4].grammarSymbols = grammarSymbols
-
-	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.IDENT))
-	options[5].grammarSymbols = grammarSymbols
-
-	productions[VAR].options = options
-	productions[VAR].head = VAR
+	productions[ENUM_TYPE].options = options
+	productions[ENUM_TYPE].head = ENUM_TYPE
 
-	// ADDRESS:
-	options = make([]Option, 1)
+	//ENUM_VALUES: the values are chained by commas
+	options = make([]Option, 3)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.DOLLAR))
-	grammarSymbols = append(grammarSymbols, productions[VAR])
+	grammarSymbols = append(grammarSymbols, productions[ENUM_VALUE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.COMMA))
+	grammarSymbols = append(grammarSymbols, productions[ENUM_VALUES])
 	options[0].grammarSymbols = grammarSymbols
 
-	productions[ADDRESS].options = options
-	productions[ADDRESS].head = ADDRESS
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[ENUM_VALUE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.COMMA))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[ENUM_VALUES])
+	options[1].grammarSymbols = grammarSymbols
 
-	// LITERAL:
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[ENUM_VALUE])
+	options[2].grammarSymbols = grammarSymbols
+
+	productions[ENUM_VALUES].options = options
+	productions[ENUM_VALUES].head = ENUM_VALUES
 
+	//ENUM_VALUE: a value with an explicit byte is the left child of "=", and the byte the right one
 	options = make([]Option, 2)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.BYTE))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, Terminal(token.EQ))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
 	options[0].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.BOOL))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
 	options[1].grammarSymbols = grammarSymbols
 
-	productions[LITERAL].options = options
-	productions[LITERAL].head = LITERAL
+	productions[ENUM_VALUE].options = options
+	productions[ENUM_VALUE].head = ENUM_VALUE
 
-	//ARGS:
+	//LOOP_STATEMENT: the loops that can be labeled
 	options = make([]Option, 2)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
-	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	grammarSymbols = append(grammarSymbols, productions[WHILE_STATEMENT])
+
 	options[0].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
-	grammarSymbols = append(grammarSymbols, productions[PARAM_DECLARATION])
-	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	grammarSymbols = append(grammarSymbols, productions[FOR_STATEMENT])
+
 	options[1].grammarSymbols = grammarSymbols
 
-	productions[ARGS].options = options
-	productions[ARGS].head = ARGS
+	productions[LOOP_STATEMENT].options = options
+	productions[LOOP_STATEMENT].head = LOOP_STATEMENT
 
-	//NEW_LINE:
-	options = make([]Option, 2)
+	//LOOP_CONTROL:
+	options = make([]Option, 4)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.NEWLINE))
-	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.BREAK))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+
 	options[0].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, Terminal(token.NEWLINE))
+	grammarSymbols = append(grammarSymbols, Terminal(token.BREAK))
+
 	options[1].grammarSymbols = grammarSymbols
 
-	productions[NEW_LINE].options = options
-	productions[NEW_LINE].head = NEW_LINE
-	//EXPRESSION_P0:
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.CONTINUE))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+
+	options[2].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.CONTINUE))
+
+	options[3].grammarSymbols = grammarSymbols
+
+	productions[LOOP_CONTROL].options = options
+	productions[LOOP_CONTROL].head = LOOP_CONTROL
+
+	//STATEMENT
+	options = make([]Option, 21)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[DECLARATION])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[1].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.FUNCTION))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, productions[ARGS])
+	grammarSymbols = append(grammarSymbols, productions[FUNC_DATATYPE])
+	grammarSymbols = append(grammarSymbols, productions[FUNC_BLOCK])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[2].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[CALL])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[3].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[VAR])
+	grammarSymbols = append(grammarSymbols, Terminal(token.EQ))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[4].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[WHILE_STATEMENT])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[5].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.IF))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, productions[BLOCK])
+	grammarSymbols = append(grammarSymbols, Terminal(token.ELSE))
+	grammarSymbols = append(grammarSymbols, productions[BLOCK])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[6].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.IF))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, productions[BLOCK])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[7].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[RETURN_STATEMENT])
+
+	options[8].grammarSymbols = grammarSymbols
+
+	//a labeled loop: the label is the left child of ":" and the loop the right one
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, Terminal(token.COLON))
+	grammarSymbols = append(grammarSymbols, productions[LOOP_STATEMENT])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[9].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[LOOP_CONTROL])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[10].grammarSymbols = grammarSymbols
+
+	//a declaration with an initialization: the initialization is the third child of "let"
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LET))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	grammarSymbols = append(grammarSymbols, productions[INITIALIZATION])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[11].grammarSymbols = grammarSymbols
+
+	//a declaration whose data type is inferred from the initialization: the initialization is the second child of "let"
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LET))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, productions[INITIALIZATION])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[12].grammarSymbols = grammarSymbols
+
+	//a constant, whose value is the child of the initialization
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.CONST))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	grammarSymbols = append(grammarSymbols, productions[INITIALIZATION])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[13].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.CONST))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, productions[INITIALIZATION])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[14].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[FOR_STATEMENT])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[15].grammarSymbols = grammarSymbols
+
+	//a compound assignation: the variable is the left child of the operator and the value the right one
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[VAR])
+	grammarSymbols = append(grammarSymbols, Terminal(COMPOUND_ASSIGNMENT))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[16].grammarSymbols = grammarSymbols
+
+	//an increment or a decrement: the variable is the only child of the operator
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[VAR])
+	grammarSymbols = append(grammarSymbols, Terminal(INCREMENT))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[17].grammarSymbols = grammarSymbols
+
+	//a switch: the subject is the left child of "switch" and the block with the clauses the right one
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.SWITCH))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, productions[SWITCH_BLOCK])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[18].grammarSymbols = grammarSymbols
+
+	//a struct type declaration: the name is the left child of "type" and the struct the right one
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.TYPE))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, productions[STRUCT_TYPE])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[19].grammarSymbols = grammarSymbols
+
+	//an enum declaration: the name is the left child of "enum" and the block with the values the right one
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.ENUM))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, productions[ENUM_TYPE])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+
+	options[20].grammarSymbols = grammarSymbols
+
+	productions[STATEMENT].options = options
+	productions[STATEMENT].head = STATEMENT
+
+	//DECLARATION
+	options = make([]Option, 1)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LET))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	options[0].grammarSymbols = grammarSymbols
+
+	productions[DECLARATION].options = options
+	productions[DECLARATION].head = DECLARATION
+
+	//INITIALIZATION
+	options = make([]Option, 3)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.EQ))
+	grammarSymbols = append(grammarSymbols, productions[ARRAY_LITERAL])
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.EQ))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	options[1].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.EQ))
+	grammarSymbols = append(grammarSymbols, productions[EMBED_LITERAL])
+	options[2].grammarSymbols = grammarSymbols
+
+	productions[INITIALIZATION].options = options
+	productions[INITIALIZATION].head = INITIALIZATION
+
+	//EMBED_LITERAL: the parenthesis is a child of "embed", and the path is the child of the parenthesis
+	options = make([]Option, 1)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.EMBED))
+	grammarSymbols = append(grammarSymbols, productions[EMBED_ARG])
+	options[0].grammarSymbols = grammarSymbols
+
+	productions[EMBED_LITERAL].options = options
+	productions[EMBED_LITERAL].head = EMBED_LITERAL
+
+	//EMBED_ARG
+	options = make([]Option, 1)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
+	grammarSymbols = append(grammarSymbols, productions[FILE_PATH])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	options[0].grammarSymbols = grammarSymbols
+
+	productions[EMBED_ARG].options = options
+	productions[EMBED_ARG].head = EMBED_ARG
+
+	//FILE_PATH
+	options = make([]Option, 1)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.STRING))
+	options[0].grammarSymbols = grammarSymbols
+
+	productions[FILE_PATH].options = options
+	productions[FILE_PATH].head = FILE_PATH
+
+	//SIZE_OPERATOR: as in embed, the parenthesis is a child of "len" or "sizeof", and the argument is the child of the parenthesis
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LEN))
+	grammarSymbols = append(grammarSymbols, productions[SIZE_ARG])
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.SIZEOF))
+	grammarSymbols = append(grammarSymbols, productions[SIZE_ARG])
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[SIZE_OPERATOR].options = options
+	productions[SIZE_OPERATOR].head = SIZE_OPERATOR
+
+	//SIZE_ARG: an expression is tried first, so a variable is not taken as the name of a type
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
+	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[SIZE_ARG].options = options
+	productions[SIZE_ARG].head = SIZE_ARG
+
+	//ARRAY_LITERAL: the elements can be written in several lines
 	options = make([]Option, 4)
 
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[ELEMENTS])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[ELEMENTS])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
+	options[1].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[ELEMENTS])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
+	options[2].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[ELEMENTS])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
+	options[3].grammarSymbols = grammarSymbols
+
+	productions[ARRAY_LITERAL].options = options
+	productions[ARRAY_LITERAL].head = ARRAY_LITERAL
+
+	//ELEMENTS
+	options = make([]Option, 3)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[ELEMENT])
+	grammarSymbols = append(grammarSymbols, Terminal(token.COMMA))
+	grammarSymbols = append(grammarSymbols, productions[ELEMENTS])
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[ELEMENT])
+	grammarSymbols = append(grammarSymbols, Terminal(token.COMMA))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[ELEMENTS])
+	options[1].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[ELEMENT])
+	options[2].grammarSymbols = grammarSymbols
+
+	productions[ELEMENTS].options = options
+	productions[ELEMENTS].head = ELEMENTS
+
+	//ELEMENT
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[ARRAY_LITERAL])
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[ELEMENT].options = options
+	productions[ELEMENT].head = ELEMENT
+
+	//SPRITE_LITERAL: the block is a child of "sprite", and each row is the child of the previous one
+	options = make([]Option, 1)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.SPRITE))
+	grammarSymbols = append(grammarSymbols, productions[SPRITE_BLOCK])
+	options[0].grammarSymbols = grammarSymbols
+
+	productions[SPRITE_LITERAL].options = options
+	productions[SPRITE_LITERAL].head = SPRITE_LITERAL
+
+	//SPRITE_BLOCK
+	options = make([]Option, 4)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[SPRITE_ROWS])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[SPRITE_ROWS])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
+	options[1].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[SPRITE_ROWS])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
+	options[2].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACE))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[SPRITE_ROWS])
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACE))
+	options[3].grammarSymbols = grammarSymbols
+
+	productions[SPRITE_BLOCK].options = options
+	productions[SPRITE_BLOCK].head = SPRITE_BLOCK
+
+	//SPRITE_ROWS
+	options = make([]Option, 3)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.STRING))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	grammarSymbols = append(grammarSymbols, productions[SPRITE_ROWS])
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.STRING))
+	grammarSymbols = append(grammarSymbols, productions[SPRITE_ROWS])
+	options[1].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.STRING))
+	options[2].grammarSymbols = grammarSymbols
+
+	productions[SPRITE_ROWS].options = options
+	productions[SPRITE_ROWS].head = SPRITE_ROWS
+
+	// IDENT
+	options = make([]Option, 1)
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.IDENT))
+	options[0].grammarSymbols = grammarSymbols
+
+	productions[IDENT].options = options
+	productions[IDENT].head = IDENT
+
+	// CALL
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
+	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
+	grammarSymbols = append(grammarSymbols, productions[PARAMS])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[CALL].options = options
+	productions[CALL].head = CALL
+
+	//PARAM DECLARATION
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[DECLARATION])
+	grammarSymbols = append(grammarSymbols, Terminal(token.COMMA))
+	grammarSymbols = append(grammarSymbols, productions[PARAM_DECLARATION])
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[DECLARATION])
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[PARAM_DECLARATION].options = options
+	productions[PARAM_DECLARATION].head = PARAM_DECLARATION
+	//PARAMS
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, Terminal(token.COMMA))
+	grammarSymbols = append(grammarSymbols, productions[PARAMS])
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[PARAMS].options = options
+	productions[PARAMS].head = PARAMS
+
+	// DATATYPE
+	options = make([]Option, 8)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.ASTERISK))
+	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACKET))
+	grammarSymbols = append(grammarSymbols, productions[LITERAL])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACKET))
+	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	options[1].grammarSymbols = grammarSymbols
+
+	//the length of an array can also be a constant
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACKET))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACKET))
+	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	options[2].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.TYPEBOOL))
+	options[3].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.TYPEBYTE))
+	options[4].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.TYPEWORD))
+	options[5].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.TYPEINT8))
+	options[6].grammarSymbols = grammarSymbols
+
+	//the name of a struct type
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	options[7].grammarSymbols = grammarSymbols
+
+	productions[DATATYPE].options = options
+	productions[DATATYPE].head = DATATYPE
+
+	//FUNC DATA TYPE:
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.VOID))
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[FUNC_DATATYPE].options = options
+	productions[FUNC_DATATYPE].head = FUNC_DATATYPE
+
+	//VAR: a field access is applied to the variable the path before it leads to. The field is the right child of "."
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[VAR])
+	grammarSymbols = append(grammarSymbols, Terminal(token.DOT))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[VAR_PATH])
+
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[VAR].options = options
+	productions[VAR].head = VAR
+
+	//VAR_PATH: the dereferences and indexes that lead to a variable
+	options = make([]Option, 6)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.ASTERISK))
+	grammarSymbols = append(grammarSymbols, productions[VAR_PATH])
+
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACKET))
+	grammarSymbols = append(grammarSymbols, productions[LITERAL])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACKET))
+	grammarSymbols = append(grammarSymbols, productions[VAR_PATH])
+	options[1].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LBRACKET))
+	grammarSymbols = append(grammarSymbols, productions[IDENT])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RBRACKET))
+	grammarSymbols = append(grammarSymbols, productions[VAR_PATH])
+	options[2].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[ADDRESS])
+	options[3].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[CALL])
+	options[4].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.IDENT))
+	options[5].grammarSymbols = grammarSymbols
+
+	productions[VAR_PATH].options = options
+	productions[VAR_PATH].head = VAR_PATH
+
+	// ADDRESS:
+	options = make([]Option, 1)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.DOLLAR))
+	grammarSymbols = append(grammarSymbols, productions[VAR])
+	options[0].grammarSymbols = grammarSymbols
+
+	productions[ADDRESS].options = options
+	productions[ADDRESS].head = ADDRESS
+
+	// LITERAL:
+
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.BYTE))
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.BOOL))
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[LITERAL].options = options
+	productions[LITERAL].head = LITERAL
+
+	//ARGS:
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
+	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
+	grammarSymbols = append(grammarSymbols, productions[PARAM_DECLARATION])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[ARGS].options = options
+	productions[ARGS].head = ARGS
+
+	//NEW_LINE:
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.NEWLINE))
+	grammarSymbols = append(grammarSymbols, productions[NEW_LINE])
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.NEWLINE))
+	options[1].grammarSymbols = grammarSymbols
+
+	productions[NEW_LINE].options = options
+	productions[NEW_LINE].head = NEW_LINE
+	//EXPRESSION_P0:
+	options = make([]Option, 7)
+
 	grammarSymbols = make([]GrammarSymbol, 0)
 	grammarSymbols = append(grammarSymbols, productions[LITERAL])
 	options[0].grammarSymbols = grammarSymbols
@@ -721,11 +1488,27 @@ func GetGrammar() map[string]*NonTerminal {
 	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
 	options[3].grammarSymbols = grammarSymbols
 
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[SPRITE_LITERAL])
+	options[4].grammarSymbols = grammarSymbols
+
+	//a conversion, it goes after var so "*f(x)" is still the dereference of a call
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[DATATYPE])
+	grammarSymbols = append(grammarSymbols, Terminal(token.LPAREN))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, Terminal(token.RPAREN))
+	options[5].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[SIZE_OPERATOR])
+	options[6].grammarSymbols = grammarSymbols
+
 	productions[EXPRESSION_P0].options = options
 	productions[EXPRESSION_P0].head = EXPRESSION_P0
 
 	//EXPRESSION_P1:
-	options = make([]Option, 3)
+	options = make([]Option, 5)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
 	grammarSymbols = append(grammarSymbols, Terminal(token.BANG))
@@ -738,9 +1521,19 @@ func GetGrammar() map[string]*NonTerminal {
 	options[1].grammarSymbols = grammarSymbols
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[EXPRESSION_P0])
+	grammarSymbols = append(grammarSymbols, Terminal(token.MINUS))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION_P1])
 	options[2].grammarSymbols = grammarSymbols
 
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, Terminal(token.TILDE))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION_P1])
+	options[3].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION_P0])
+	options[4].grammarSymbols = grammarSymbols
+
 	productions[EXPRESSION_P1].options = options
 	productions[EXPRESSION_P1].head = EXPRESSION_P1
 
@@ -890,12 +1683,12 @@ func GetGrammar() map[string]*NonTerminal {
 	productions[EXPRESSION_P10].options = options
 	productions[EXPRESSION_P10].head = EXPRESSION_P10
 
-	//EXPRESSION
+	//EXPRESSION_P11
 	options = make([]Option, 2)
 
 	grammarSymbols = make([]GrammarSymbol, 0)
-	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
-	grammarSymbols = append(grammarSymbols, Terminal(EXPRESSION))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION_P11])
+	grammarSymbols = append(grammarSymbols, Terminal(EXPRESSION_P11))
 	grammarSymbols = append(grammarSymbols, productions[EXPRESSION_P10])
 	options[0].grammarSymbols = grammarSymbols
 
@@ -903,6 +1696,34 @@ func GetGrammar() map[string]*NonTerminal {
 	grammarSymbols = append(grammarSymbols, productions[EXPRESSION_P10])
 	options[1].grammarSymbols = grammarSymbols
 
+	productions[EXPRESSION_P11].options = options
+	productions[EXPRESSION_P11].head = EXPRESSION_P11
+
+	//CONDITIONAL_BRANCHES: the value if the condition is true is the left child of ":", and the value if not the right one
+	options = make([]Option, 1)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	grammarSymbols = append(grammarSymbols, Terminal(token.COLON))
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION])
+	options[0].grammarSymbols = grammarSymbols
+
+	productions[CONDITIONAL_BRANCHES].options = options
+	productions[CONDITIONAL_BRANCHES].head = CONDITIONAL_BRANCHES
+
+	//EXPRESSION: a conditional expression has the condition as the left child of "?", and the branches as the right one
+	options = make([]Option, 2)
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION_P11])
+	grammarSymbols = append(grammarSymbols, Terminal(token.QUESTION))
+	grammarSymbols = append(grammarSymbols, productions[CONDITIONAL_BRANCHES])
+	options[0].grammarSymbols = grammarSymbols
+
+	grammarSymbols = make([]GrammarSymbol, 0)
+	grammarSymbols = append(grammarSymbols, productions[EXPRESSION_P11])
+	options[1].grammarSymbols = grammarSymbols
+
 	productions[EXPRESSION].options = options
 	productions[EXPRESSION].head = EXPRESSION
 
@@ -610,6 +610,593 @@ func TestBuild(t *testing.T) {
 				"/EOF/}/fn/}/return/)\n" +
 				"/EOF/}/fn/}/return/)/call\n",
 		},
+		{
+			description: "fn myFunc3() byte {if true {return 1} return 2}",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.FUNCTION, "fn", 0),
+				token.NewToken(token.IDENT, "myFunc3", 0),
+				token.NewToken(token.LPAREN, token.LPAREN, 0),
+				token.NewToken(token.RPAREN, token.RPAREN, 0),
+				token.NewToken(token.TYPEBYTE, "byte", 0),
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.IF, "if", 1),
+				token.NewToken(token.BOOL, "true", 1),
+				token.NewToken(token.LBRACE, token.LBRACE, 1),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 1),
+				token.NewToken(token.RETURN, "return", 2),
+				token.NewToken(token.BYTE, "1", 2),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 2),
+				token.NewToken(token.RBRACE, token.RBRACE, 3),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 3),
+				token.NewToken(token.RETURN, "return", 4),
+				token.NewToken(token.BYTE, "2", 4),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 4),
+				token.NewToken(token.RBRACE, token.RBRACE, 5),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 5),
+				token.NewToken(token.RBRACE, token.RBRACE, 6),
+				token.NewToken(token.EOF, token.EOF, 6),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/fn\n" +
+				"/EOF/}/fn/myFunc3\n" +
+				"/EOF/}/fn/)\n" +
+				"/EOF/}/fn/byte\n" +
+				"/EOF/}/fn/}\n" +
+				"/EOF/}/fn/}/if\n" +
+				"/EOF/}/fn/}/if/true\n" +
+				"/EOF/}/fn/}/if/}\n" +
+				"/EOF/}/fn/}/if/}/return\n" +
+				"/EOF/}/fn/}/if/}/return/1\n" +
+				"/EOF/}/fn/}/return\n" +
+				"/EOF/}/fn/}/return/2\n",
+		},
+		{
+			description: "outer: while true {continue outer break}",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.IDENT, "outer", 0),
+				token.NewToken(token.COLON, token.COLON, 0),
+				token.NewToken(token.WHILE, "while", 0),
+				token.NewToken(token.BOOL, "true", 0),
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.CONTINUE, "continue", 1),
+				token.NewToken(token.IDENT, "outer", 1),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 1),
+				token.NewToken(token.BREAK, "break", 2),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 2),
+				token.NewToken(token.RBRACE, token.RBRACE, 3),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 3),
+				token.NewToken(token.RBRACE, token.RBRACE, 4),
+				token.NewToken(token.EOF, token.EOF, 4),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/:\n" +
+				"/EOF/}/:/outer\n" +
+				"/EOF/}/:/while\n" +
+				"/EOF/}/:/while/true\n" +
+				"/EOF/}/:/while/}\n" +
+				"/EOF/}/:/while/}/continue\n" +
+				"/EOF/}/:/while/}/continue/outer\n" +
+				"/EOF/}/:/while/}/break\n",
+		},
+		{
+			description: "let eye [2]byte = {255, \\n 129}",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.LET, "let", 0),
+				token.NewToken(token.IDENT, "eye", 0),
+				token.NewToken(token.LBRACKET, token.LBRACKET, 0),
+				token.NewToken(token.BYTE, "2", 0),
+				token.NewToken(token.RBRACKET, token.RBRACKET, 0),
+				token.NewToken(token.TYPEBYTE, "byte", 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.BYTE, "255", 0),
+				token.NewToken(token.COMMA, token.COMMA, 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.BYTE, "129", 1),
+				token.NewToken(token.RBRACE, token.RBRACE, 1),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 1),
+				token.NewToken(token.RBRACE, token.RBRACE, 2),
+				token.NewToken(token.EOF, token.EOF, 2),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/let\n" +
+				"/EOF/}/let/eye\n" +
+				"/EOF/}/let/]\n" +
+				"/EOF/}/let/]/2\n" +
+				"/EOF/}/let/]/byte\n" +
+				"/EOF/}/let/=\n" +
+				"/EOF/}/let/=/}\n" +
+				"/EOF/}/let/=/}/,\n" +
+				"/EOF/}/let/=/}/,/255\n" +
+				"/EOF/}/let/=/}/,/129\n",
+		},
+		{
+			description: "let foo = bar + 1",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.LET, "let", 0),
+				token.NewToken(token.IDENT, "foo", 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.IDENT, "bar", 0),
+				token.NewToken(token.PLUS, token.PLUS, 0),
+				token.NewToken(token.BYTE, "1", 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.RBRACE, token.RBRACE, 1),
+				token.NewToken(token.EOF, token.EOF, 1),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/let\n" +
+				"/EOF/}/let/foo\n" +
+				"/EOF/}/let/=\n" +
+				"/EOF/}/let/=/+\n" +
+				"/EOF/}/let/=/+/bar\n" +
+				"/EOF/}/let/=/+/1\n",
+		},
+		{
+			description: "const N byte = 2 let foo [N]byte",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.CONST, "const", 0),
+				token.NewToken(token.IDENT, "N", 0),
+				token.NewToken(token.TYPEBYTE, "byte", 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.BYTE, "2", 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.LET, "let", 1),
+				token.NewToken(token.IDENT, "foo", 1),
+				token.NewToken(token.LBRACKET, token.LBRACKET, 1),
+				token.NewToken(token.IDENT, "N", 1),
+				token.NewToken(token.RBRACKET, token.RBRACKET, 1),
+				token.NewToken(token.TYPEBYTE, "byte", 1),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 1),
+				token.NewToken(token.RBRACE, token.RBRACE, 2),
+				token.NewToken(token.EOF, token.EOF, 2),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/const\n" +
+				"/EOF/}/const/N\n" +
+				"/EOF/}/const/byte\n" +
+				"/EOF/}/const/=\n" +
+				"/EOF/}/const/=/2\n" +
+				"/EOF/}/let\n" +
+				"/EOF/}/let/foo\n" +
+				"/EOF/}/let/]\n" +
+				"/EOF/}/let/]/N\n" +
+				"/EOF/}/let/]/byte\n",
+		},
+		{
+			description: "draw(0, 2, sprite {\"#.\" \n \".#\" \n})",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.IDENT, "draw", 0),
+				token.NewToken(token.LPAREN, token.LPAREN, 0),
+				token.NewToken(token.BYTE, "0", 0),
+				token.NewToken(token.COMMA, token.COMMA, 0),
+				token.NewToken(token.BYTE, "2", 0),
+				token.NewToken(token.COMMA, token.COMMA, 0),
+				token.NewToken(token.SPRITE, "sprite", 0),
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.STRING, "#.", 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.STRING, ".#", 1),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 1),
+				token.NewToken(token.RBRACE, token.RBRACE, 2),
+				token.NewToken(token.RPAREN, token.RPAREN, 2),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 2),
+				token.NewToken(token.RBRACE, token.RBRACE, 3),
+				token.NewToken(token.EOF, token.EOF, 3),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/)\n" +
+				"/EOF/}/)/draw\n" +
+				"/EOF/}/)/,\n" +
+				"/EOF/}/)/,/0\n" +
+				"/EOF/}/)/,/,\n" +
+				"/EOF/}/)/,/,/2\n" +
+				"/EOF/}/)/,/,/sprite\n" +
+				"/EOF/}/)/,/,/sprite/}\n" +
+				"/EOF/}/)/,/,/sprite/}/#.\n" +
+				"/EOF/}/)/,/,/sprite/}/#./.#\n",
+		},
+		{
+			description: "let foo = embed(\"foo.pbm\")",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.LET, "let", 0),
+				token.NewToken(token.IDENT, "foo", 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.EMBED, "embed", 0),
+				token.NewToken(token.LPAREN, token.LPAREN, 0),
+				token.NewToken(token.STRING, "foo.pbm", 0),
+				token.NewToken(token.RPAREN, token.RPAREN, 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.RBRACE, token.RBRACE, 1),
+				token.NewToken(token.EOF, token.EOF, 1),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/let\n" +
+				"/EOF/}/let/foo\n" +
+				"/EOF/}/let/=\n" +
+				"/EOF/}/let/=/embed\n" +
+				"/EOF/}/let/=/embed/)\n" +
+				"/EOF/}/let/=/embed/)/foo.pbm\n",
+		},
+		{
+			description: "for let i byte = 0; i < 8; i = i + 1 {\n}",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.FOR, "for", 0),
+				token.NewToken(token.LET, "let", 0),
+				token.NewToken(token.IDENT, "i", 0),
+				token.NewToken(token.TYPEBYTE, "byte", 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.BYTE, "0", 0),
+				token.NewToken(token.SEMICOLON, token.SEMICOLON, 0),
+				token.NewToken(token.IDENT, "i", 0),
+				token.NewToken(token.LT, token.LT, 0),
+				token.NewToken(token.BYTE, "8", 0),
+				token.NewToken(token.SEMICOLON, token.SEMICOLON, 0),
+				token.NewToken(token.IDENT, "i", 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.IDENT, "i", 0),
+				token.NewToken(token.PLUS, token.PLUS, 0),
+				token.NewToken(token.BYTE, "1", 0),
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.RBRACE, token.RBRACE, 1),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 1),
+				token.NewToken(token.RBRACE, token.RBRACE, 2),
+				token.NewToken(token.EOF, token.EOF, 2),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/for\n" +
+				"/EOF/}/for/let\n" +
+				"/EOF/}/for/let/i\n" +
+				"/EOF/}/for/let/byte\n" +
+				"/EOF/}/for/let/=\n" +
+				"/EOF/}/for/let/=/0\n" +
+				"/EOF/}/for/<\n" +
+				"/EOF/}/for/</i\n" +
+				"/EOF/}/for/</8\n" +
+				"/EOF/}/for/=\n" +
+				"/EOF/}/for/=/i\n" +
+				"/EOF/}/for/=/+\n" +
+				"/EOF/}/for/=/+/i\n" +
+				"/EOF/}/for/=/+/1\n" +
+				"/EOF/}/for/}\n",
+		},
+		{
+			description: "[0]foo += 2 \n bar++",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.LBRACKET, token.LBRACKET, 0),
+				token.NewToken(token.BYTE, "0", 0),
+				token.NewToken(token.RBRACKET, token.RBRACKET, 0),
+				token.NewToken(token.IDENT, "foo", 0),
+				token.NewToken(token.PLUSEQ, token.PLUSEQ, 0),
+				token.NewToken(token.BYTE, "2", 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.IDENT, "bar", 1),
+				token.NewToken(token.PLUSPLUS, token.PLUSPLUS, 1),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 1),
+				token.NewToken(token.RBRACE, token.RBRACE, 2),
+				token.NewToken(token.EOF, token.EOF, 2),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/+=\n" +
+				"/EOF/}/+=/]\n" +
+				"/EOF/}/+=/]/0\n" +
+				"/EOF/}/+=/]/foo\n" +
+				"/EOF/}/+=/2\n" +
+				"/EOF/}/++\n" +
+				"/EOF/}/++/bar\n",
+		},
+		{
+			description: "foo = 1 - -~bar",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.IDENT, "foo", 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.BYTE, "1", 0),
+				token.NewToken(token.MINUS, token.MINUS, 0),
+				token.NewToken(token.MINUS, token.MINUS, 0),
+				token.NewToken(token.TILDE, token.TILDE, 0),
+				token.NewToken(token.IDENT, "bar", 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.RBRACE, token.RBRACE, 1),
+				token.NewToken(token.EOF, token.EOF, 1),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/=\n" +
+				"/EOF/}/=/foo\n" +
+				"/EOF/}/=/-\n" +
+				"/EOF/}/=/-/1\n" +
+				"/EOF/}/=/-/-\n" +
+				"/EOF/}/=/-/-/~\n" +
+				"/EOF/}/=/-/-/~/bar\n",
+		},
+		{
+			description: "switch foo { case 1, 2 {} default {} }",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.SWITCH, token.SWITCH, 0),
+				token.NewToken(token.IDENT, "foo", 0),
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.CASE, token.CASE, 1),
+				token.NewToken(token.BYTE, "1", 1),
+				token.NewToken(token.COMMA, token.COMMA, 1),
+				token.NewToken(token.BYTE, "2", 1),
+				token.NewToken(token.LBRACE, token.LBRACE, 1),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 1),
+				token.NewToken(token.RBRACE, token.RBRACE, 2),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 2),
+				token.NewToken(token.DEFAULT, token.DEFAULT, 3),
+				token.NewToken(token.LBRACE, token.LBRACE, 3),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 3),
+				token.NewToken(token.RBRACE, token.RBRACE, 4),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 4),
+				token.NewToken(token.RBRACE, token.RBRACE, 5),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 5),
+				token.NewToken(token.RBRACE, token.RBRACE, 6),
+				token.NewToken(token.EOF, token.EOF, 6),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/switch\n" +
+				"/EOF/}/switch/foo\n" +
+				"/EOF/}/switch/}\n" +
+				"/EOF/}/switch/}/case\n" +
+				"/EOF/}/switch/}/case/,\n" +
+				"/EOF/}/switch/}/case/,/1\n" +
+				"/EOF/}/switch/}/case/,/2\n" +
+				"/EOF/}/switch/}/case/}\n" +
+				"/EOF/}/switch/}/default\n" +
+				"/EOF/}/switch/}/default/}\n",
+		},
+		{
+			description: "foo = a || b ? 1 : c ? 2 : 3",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.IDENT, "foo", 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.IDENT, "a", 0),
+				token.NewToken(token.LOR, token.LOR, 0),
+				token.NewToken(token.IDENT, "b", 0),
+				token.NewToken(token.QUESTION, token.QUESTION, 0),
+				token.NewToken(token.BYTE, "1", 0),
+				token.NewToken(token.COLON, token.COLON, 0),
+				token.NewToken(token.IDENT, "c", 0),
+				token.NewToken(token.QUESTION, token.QUESTION, 0),
+				token.NewToken(token.BYTE, "2", 0),
+				token.NewToken(token.COLON, token.COLON, 0),
+				token.NewToken(token.BYTE, "3", 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.RBRACE, token.RBRACE, 1),
+				token.NewToken(token.EOF, token.EOF, 1),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/=\n" +
+				"/EOF/}/=/foo\n" +
+				"/EOF/}/=/?\n" +
+				"/EOF/}/=/?/||\n" +
+				"/EOF/}/=/?/||/a\n" +
+				"/EOF/}/=/?/||/b\n" +
+				"/EOF/}/=/?/:\n" +
+				"/EOF/}/=/?/:/1\n" +
+				"/EOF/}/=/?/:/?\n" +
+				"/EOF/}/=/?/:/?/c\n" +
+				"/EOF/}/=/?/:/?/:\n" +
+				"/EOF/}/=/?/:/?/:/2\n" +
+				"/EOF/}/=/?/:/?/:/3\n",
+		},
+		{
+			description: "type Entity struct { x byte; spr *byte \n } \n [1]e.x = p.pos.y",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.TYPE, token.TYPE, 0),
+				token.NewToken(token.IDENT, "Entity", 0),
+				token.NewToken(token.STRUCT, token.STRUCT, 0),
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.IDENT, "x", 0),
+				token.NewToken(token.TYPEBYTE, token.TYPEBYTE, 0),
+				token.NewToken(token.SEMICOLON, token.SEMICOLON, 0),
+				token.NewToken(token.IDENT, "spr", 0),
+				token.NewToken(token.ASTERISK, token.ASTERISK, 0),
+				token.NewToken(token.TYPEBYTE, token.TYPEBYTE, 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.RBRACE, token.RBRACE, 1),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 1),
+				token.NewToken(token.LBRACKET, token.LBRACKET, 2),
+				token.NewToken(token.BYTE, "1", 2),
+				token.NewToken(token.RBRACKET, token.RBRACKET, 2),
+				token.NewToken(token.IDENT, "e", 2),
+				token.NewToken(token.DOT, token.DOT, 2),
+				token.NewToken(token.IDENT, "x", 2),
+				token.NewToken(token.EQ, token.EQ, 2),
+				token.NewToken(token.IDENT, "p", 2),
+				token.NewToken(token.DOT, token.DOT, 2),
+				token.NewToken(token.IDENT, "pos", 2),
+				token.NewToken(token.DOT, token.DOT, 2),
+				token.NewToken(token.IDENT, "y", 2),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 2),
+				token.NewToken(token.RBRACE, token.RBRACE, 3),
+				token.NewToken(token.EOF, token.EOF, 3),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/type\n" +
+				"/EOF/}/type/Entity\n" +
+				"/EOF/}/type/}\n" +
+				"/EOF/}/type/}/x\n" +
+				"/EOF/}/type/}/TYPEBYTE\n" +
+				"/EOF/}/type/}/spr\n" +
+				"/EOF/}/type/}/*\n" +
+				"/EOF/}/type/}/*/TYPEBYTE\n" +
+				"/EOF/}/=\n" +
+				"/EOF/}/=/.\n" +
+				"/EOF/}/=/./]\n" +
+				"/EOF/}/=/./]/1\n" +
+				"/EOF/}/=/./]/e\n" +
+				"/EOF/}/=/./x\n" +
+				"/EOF/}/=/.\n" +
+				"/EOF/}/=/./.\n" +
+				"/EOF/}/=/././p\n" +
+				"/EOF/}/=/././pos\n" +
+				"/EOF/}/=/./y\n",
+		},
+		{
+			description: "let w word = word(b) + 300",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.LET, token.LET, 0),
+				token.NewToken(token.IDENT, "w", 0),
+				token.NewToken(token.TYPEWORD, token.TYPEWORD, 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.TYPEWORD, token.TYPEWORD, 0),
+				token.NewToken(token.LPAREN, token.LPAREN, 0),
+				token.NewToken(token.IDENT, "b", 0),
+				token.NewToken(token.RPAREN, token.RPAREN, 0),
+				token.NewToken(token.PLUS, token.PLUS, 0),
+				token.NewToken(token.BYTE, "300", 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.RBRACE, token.RBRACE, 1),
+				token.NewToken(token.EOF, token.EOF, 1),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/let\n" +
+				"/EOF/}/let/w\n" +
+				"/EOF/}/let/TYPEWORD\n" +
+				"/EOF/}/let/=\n" +
+				"/EOF/}/let/=/+\n" +
+				"/EOF/}/let/=/+/)\n" +
+				"/EOF/}/let/=/+/)/TYPEWORD\n" +
+				"/EOF/}/let/=/+/)/b\n" +
+				"/EOF/}/let/=/+/300\n",
+		},
+		{
+			description: "let p *byte = *byte(a)",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.LET, token.LET, 0),
+				token.NewToken(token.IDENT, "p", 0),
+				token.NewToken(token.ASTERISK, token.ASTERISK, 0),
+				token.NewToken(token.TYPEBYTE, token.TYPEBYTE, 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.ASTERISK, token.ASTERISK, 0),
+				token.NewToken(token.TYPEBYTE, token.TYPEBYTE, 0),
+				token.NewToken(token.LPAREN, token.LPAREN, 0),
+				token.NewToken(token.IDENT, "a", 0),
+				token.NewToken(token.RPAREN, token.RPAREN, 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.RBRACE, token.RBRACE, 1),
+				token.NewToken(token.EOF, token.EOF, 1),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/let\n" +
+				"/EOF/}/let/p\n" +
+				"/EOF/}/let/*\n" +
+				"/EOF/}/let/*/TYPEBYTE\n" +
+				"/EOF/}/let/=\n" +
+				"/EOF/}/let/=/)\n" +
+				"/EOF/}/let/=/)/*\n" +
+				"/EOF/}/let/=/)/*/TYPEBYTE\n" +
+				"/EOF/}/let/=/)/a\n",
+		},
+		{
+			description: "enum D {A = 1, B}",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.ENUM, token.ENUM, 0),
+				token.NewToken(token.IDENT, "D", 0),
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.IDENT, "A", 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.BYTE, "1", 0),
+				token.NewToken(token.COMMA, token.COMMA, 0),
+				token.NewToken(token.IDENT, "B", 0),
+				token.NewToken(token.RBRACE, token.RBRACE, 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.RBRACE, token.RBRACE, 1),
+				token.NewToken(token.EOF, token.EOF, 1),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/enum\n" +
+				"/EOF/}/enum/D\n" +
+				"/EOF/}/enum/}\n" +
+				"/EOF/}/enum/}/,\n" +
+				"/EOF/}/enum/}/,/=\n" +
+				"/EOF/}/enum/}/,/=/A\n" +
+				"/EOF/}/enum/}/,/=/1\n" +
+				"/EOF/}/enum/}/,/B\n",
+		},
+		{
+			description: "x = len(a) + sizeof(byte)",
+			src: []token.Token{
+				token.NewToken(token.LBRACE, token.LBRACE, 0),
+				token.NewToken(token.IDENT, "x", 0),
+				token.NewToken(token.EQ, token.EQ, 0),
+				token.NewToken(token.LEN, token.LEN, 0),
+				token.NewToken(token.LPAREN, token.LPAREN, 0),
+				token.NewToken(token.IDENT, "a", 0),
+				token.NewToken(token.RPAREN, token.RPAREN, 0),
+				token.NewToken(token.PLUS, token.PLUS, 0),
+				token.NewToken(token.SIZEOF, token.SIZEOF, 0),
+				token.NewToken(token.LPAREN, token.LPAREN, 0),
+				token.NewToken(token.TYPEBYTE, token.TYPEBYTE, 0),
+				token.NewToken(token.RPAREN, token.RPAREN, 0),
+				token.NewToken(token.NEWLINE, token.NEWLINE, 0),
+				token.NewToken(token.RBRACE, token.RBRACE, 1),
+				token.NewToken(token.EOF, token.EOF, 1),
+			},
+			isValid: true,
+			expectedTreeRep: "\n/EOF\n" +
+				"/EOF/}\n" +
+				"/EOF/}/=\n" +
+				"/EOF/}/=/x\n" +
+				"/EOF/}/=/+\n" +
+				"/EOF/}/=/+/len\n" +
+				"/EOF/}/=/+/len/)\n" +
+				"/EOF/}/=/+/len/)/a\n" +
+				"/EOF/}/=/+/sizeof\n" +
+				"/EOF/}/=/+/sizeof/)\n" +
+				"/EOF/}/=/+/sizeof/)/TYPEBYTE\n",
+		},
 	}
 
 	for _, scenario := range testCases {
@@ -8,6 +8,8 @@ const (
 	BYTE  = "BYTE"
 	BOOL  = "BOOL"
 
+	STRING = "STRING"
+
 	TRUE  = "true"
 	FALSE = "false"
 
@@ -28,6 +30,20 @@ const (
 	LTLT     = "<<"
 	GTGT     = ">>"
 	XOR      = "^"
+	TILDE    = "~"
+
+	PLUSEQ     = "+="
+	MINUSEQ    = "-="
+	ASTERISKEQ = "*="
+	SLASHEQ    = "/="
+	PERCENTEQ  = "%="
+	ANDEQ      = "&="
+	OREQ       = "|="
+	XOREQ      = "^="
+	LTLTEQ     = "<<="
+	GTGTEQ     = ">>="
+	PLUSPLUS   = "++"
+	MINUSMINUS = "--"
 
 	LT    = "<"
 	LTEQ  = "<="
@@ -36,8 +52,12 @@ const (
 	NOTEQ = "!="
 	EQEQ  = "=="
 
-	COMMA   = ","
-	NEWLINE = "\n"
+	COMMA     = ","
+	DOT       = "."
+	COLON     = ":"
+	QUESTION  = "?"
+	SEMICOLON = ";"
+	NEWLINE   = "\n"
 
 	LPAREN   = "("
 	RPAREN   = ")"
@@ -48,14 +68,30 @@ const (
 
 	FUNCTION = "fn"
 	WHILE    = "while"
+	FOR      = "for"
 	LET      = "let"
+	CONST    = "const"
+	TYPE     = "type"
+	STRUCT   = "struct"
+	ENUM     = "enum"
+	SPRITE   = "sprite"
+	EMBED    = "embed"
+	LEN      = "len"
+	SIZEOF   = "sizeof"
 	IF       = "if"
 	ELSE     = "else"
+	SWITCH   = "switch"
+	CASE     = "case"
+	DEFAULT  = "default"
 	RETURN   = "return"
+	BREAK    = "break"
+	CONTINUE = "continue"
 	MAIN     = "main"
 
 	TYPEBOOL = "TYPEBOOL"
 	TYPEBYTE = "TYPEBYTE"
+	TYPEWORD = "TYPEWORD"
+	TYPEINT8 = "TYPEINT8"
 	VOID     = "VOID"
 )
 
@@ -73,17 +109,32 @@ func NewToken(tokenType Type, literal string, line int) Token {
 }
 
 var keywords = map[string]Type{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
-	"while":  WHILE,
-	"bool":   TYPEBOOL,
-	"byte":   TYPEBYTE,
-	"true":   BOOL,
-	"false":  BOOL,
-	"void":   VOID,
+	"fn":       FUNCTION,
+	"let":      LET,
+	"const":    CONST,
+	"type":     TYPE,
+	"struct":   STRUCT,
+	"enum":     ENUM,
+	"embed":    EMBED,
+	"len":      LEN,
+	"sizeof":   SIZEOF,
+	"if":       IF,
+	"else":     ELSE,
+	"switch":   SWITCH,
+	"case":     CASE,
+	"default":  DEFAULT,
+	"return":   RETURN,
+	"while":    WHILE,
+	"for":      FOR,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"bool":     TYPEBOOL,
+	"byte":     TYPEBYTE,
+	"word":     TYPEWORD,
+	"int8":     TYPEINT8,
+	"true":     BOOL,
+	"false":    BOOL,
+	"void":     VOID,
 }
 
 func LookupIdent(ident string) Type {
@@ -92,3 +143,26 @@ func LookupIdent(ident string) Type {
 	}
 	return IDENT
 }
+
+//compoundAssignments maps each compound assignment, increment and decrement to the operator it applies to its variable
+var compoundAssignments = map[Type]Type{
+	PLUSEQ:     PLUS,
+	MINUSEQ:    MINUS,
+	ASTERISKEQ: ASTERISK,
+	SLASHEQ:    SLASH,
+	PERCENTEQ:  PERCENT,
+	ANDEQ:      AND,
+	OREQ:       OR,
+	XOREQ:      XOR,
+	LTLTEQ:     LTLT,
+	GTGTEQ:     GTGT,
+	PLUSPLUS:   PLUS,
+	MINUSMINUS: MINUS,
+}
+
+//LookupCompoundAssignment returns the operator a compound assignment, an increment or a decrement applies,
+//and false if the token type is not one of them
+func LookupCompoundAssignment(assignment Type) (Type, bool) {
+	operator, ok := compoundAssignments[assignment]
+	return operator, ok
+}